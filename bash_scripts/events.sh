@@ -0,0 +1,38 @@
+#!/bin/bash
+# Eventos en vivo hacia gorunscript por el descriptor indicado en GORUNSCRIPT_EVENT_FD.
+# Cada evento es una línea con campos separados por tabuladores. Sin ese descriptor
+# (p. ej. al ejecutar el script a mano) las funciones no hacen nada.
+
+# Escribe un evento con sus campos, cambiando tabuladores y saltos de línea por espacios
+_emit_event() {
+  [ -n "$GORUNSCRIPT_EVENT_FD" ] || return 0
+  local IFS=$'\t' field
+  local record=()
+  for field in "$@"; do
+    field=${field//$'\t'/ }
+    field=${field//$'\n'/ }
+    record+=("$field")
+  done
+  printf '%s\n' "${record[*]}" 2>/dev/null >&"$GORUNSCRIPT_EVENT_FD"
+  return 0
+}
+
+# Empieza un paso. ej: emit_step "Subiendo cambios"
+emit_step() {
+  _emit_event step "$1"
+}
+
+# Informa del avance. ej: emit_progress 3 10 "Actualizando paquetes"
+emit_progress() {
+  _emit_event progress "$1" "$2" "$3"
+}
+
+# Informa de una advertencia. ej: emit_warning "No hay cambios que commitear"
+emit_warning() {
+  _emit_event warning "$1"
+}
+
+# Informa del resultado final. ej: emit_result ok "v0.0.2 publicada"
+emit_result() {
+  _emit_event result "$1" "$2"
+}
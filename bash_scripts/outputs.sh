@@ -0,0 +1,23 @@
+#!/bin/bash
+# Salidas con nombre hacia gorunscript en el archivo indicado en GORUNSCRIPT_RESULT_FILE.
+# Cada salida es una línea clave=valor, o clave<<DELIM ... DELIM si el valor tiene varias
+# líneas. Sin ese archivo (p. ej. al ejecutar el script a mano) las funciones no hacen nada.
+
+# Publica una salida. ej: set_output local_version "v0.0.2"
+set_output() {
+  [ -n "$GORUNSCRIPT_RESULT_FILE" ] || return 0
+  local key=$1 value=$2
+  if [[ $value == *$'\n'* ]]; then
+    local delim="EOF_${RANDOM}${RANDOM}"
+    printf '%s<<%s\n%s\n%s\n' "$key" "$delim" "$value" "$delim" >>"$GORUNSCRIPT_RESULT_FILE"
+  else
+    printf '%s=%s\n' "$key" "$value" >>"$GORUNSCRIPT_RESULT_FILE"
+  fi
+}
+
+# Publica un documento JSON completo, reemplazando las salidas anteriores.
+# ej: set_outputs_json '{"version": "v0.0.2", "files": ["a.go"]}'
+set_outputs_json() {
+  [ -n "$GORUNSCRIPT_RESULT_FILE" ] || return 0
+  printf '%s\n' "$1" >"$GORUNSCRIPT_RESULT_FILE"
+}
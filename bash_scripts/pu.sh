@@ -1,5 +1,6 @@
 #!/bin/bash
 source functions.sh
+source events.sh
 
 # Este script genera una etiqueta con un número correlativo cambiando solo el último
 # dígito del tag ej v5.4.2 el siguiente será v5.4.3
@@ -27,12 +28,15 @@ if [ -z "$commit_message" ]; then
 fi
 
 # Agrega cambios al índice
+emit_step "Añadiendo cambios"
 execute "git add ." "Error al añadir cambios a Git $current_folder." "cambios $current_folder añadidos"
 
 # Realiza el commit solo si hay cambios
 if git diff-index --quiet HEAD --; then
     echo "No hay cambios que commitear."
+    emit_warning "No hay cambios que commitear"
 else
+    emit_step "Creando commit"
     execute "git commit -m '$commit_message'" "Error al crear el nuevo commit $current_folder."
 fi
 
@@ -53,12 +57,14 @@ else
     new_tag=$(echo "$latest_tag" | sed "s/$last_number$/$next_number/")
 fi
 
+emit_step "Creando etiqueta $new_tag"
 execute "git tag $new_tag" "Error al crear la nueva etiqueta $current_folder." "nueva etiqueta $new_tag"
 
 # Verifica si la rama tiene upstream configurado
 branch=$(git symbolic-ref --short HEAD)
 upstream=$(git rev-parse --symbolic-full-name --abbrev-ref @{u} 2>/dev/null)
 
+emit_step "Subiendo cambios y etiqueta"
 if [ -z "$upstream" ]; then
     # Si no hay upstream, configura el upstream y haz push
     execute "git push --set-upstream origin $branch && git push origin $new_tag" \
@@ -71,6 +77,8 @@ else
     "Commit y Push $current_folder..."
 fi
 
+emit_result ok "$new_tag"
+
 # Imprimir los mensajes acumulados
 successMessages
 deleteChangesFileContent
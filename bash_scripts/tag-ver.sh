@@ -2,6 +2,7 @@
 
 # script para chequear version git tag local con la remota
 source functions.sh
+source outputs.sh
 
 # Verificar si hay cambios pendientes
 if [ -n "$(git status --porcelain)" ]; then
@@ -15,19 +16,23 @@ fi
 local_version=$(git describe --tags --abbrev=0)
 
 success "local_version: $local_version"
+set_output local_version "$local_version"
 
 # Obtener la versión del tag remoto
 remote_version=$(git ls-remote --tags origin | awk '{print $2}' | cut -d '/' -f 3 | sort -V | tail -n 1)
 
 
 success "remote_version: $remote_version"
+set_output remote_version "$remote_version"
 
 # Comparar las versiones
 if [ "$local_version" != "$remote_version" ]; then
+ set_output up_to_date false
 
  warning "Las versiones son diferentes. Es necesario push."
     # bash pu.sh 
 else
+ set_output up_to_date true
 
  success "Las versiones son iguales. No es necesario realizar ninguna acción."
 
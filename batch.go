@@ -0,0 +1,136 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ErrorPolicy define qué hace ExecuteMany cuando un trabajo falla
+type ErrorPolicy int
+
+const (
+	// ContinueOnError ejecuta todos los trabajos aunque alguno falle
+	ContinueOnError ErrorPolicy = iota
+	// FailFast cancela los trabajos en curso y omite los pendientes tras el primer fallo
+	FailFast
+)
+
+// errFailFast es la causa con la que FailFast cancela los trabajos en curso; envuelve
+// context.Canceled para que sus errores sigan distinguiéndose como cancelaciones
+var errFailFast = fmt.Errorf("%w: lote cancelado por el fallo de otro trabajo", context.Canceled)
+
+// Job es una ejecución dentro de un lote
+type Job struct {
+	Script  string
+	Args    []string
+	Options ExecOptions
+}
+
+// BatchOptions configura la ejecución de un lote
+type BatchOptions struct {
+	Concurrency int         // Máximo de trabajos simultáneos; <= 0 usa runtime.NumCPU()
+	Policy      ErrorPolicy // Comportamiento ante fallos
+}
+
+// JobResult es el resultado de un trabajo del lote
+type JobResult struct {
+	Job       Job
+	Result    *Result // nil si el trabajo se omitió
+	Err       error
+	Skipped   bool // El trabajo no llegó a ejecutarse por cancelación o FailFast
+	Cancelled bool // El trabajo estaba en curso y FailFast lo canceló por el fallo de otro
+}
+
+// BatchSummary resume un lote
+type BatchSummary struct {
+	Total     int
+	Succeeded int
+	Failed    int
+	Cancelled int
+	Skipped   int
+	Duration  time.Duration
+}
+
+// BatchResult contiene los resultados por trabajo, en el orden de entrada, y el resumen
+type BatchResult struct {
+	Jobs    []JobResult
+	Summary BatchSummary
+}
+
+// ExecuteMany ejecuta los trabajos con un máximo de opts.Concurrency en paralelo. Cada
+// ejecución usa su propio espacio de trabajo, por lo que es seguro ejecutar el mismo script
+// sobre varios directorios a la vez. Devuelve error si algún trabajo falló, se canceló o se
+// omitió; el error agrupa solo los de los trabajos fallidos y admite errors.Is / errors.As.
+// Los cancelados por FailFast quedan marcados en JobResult.Cancelled, con su propio error,
+// para distinguirlos del fallo que los provocó.
+func (sr *ScriptRunner) ExecuteMany(ctx context.Context, jobs []Job, opts BatchOptions) (*BatchResult, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	start := time.Now()
+	results := make([]JobResult, len(jobs))
+	pending := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(jobs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range pending {
+				results[i] = sr.runJob(ctx, jobs[i])
+				if results[i].Err != nil && !results[i].Skipped && !results[i].Cancelled && opts.Policy == FailFast {
+					cancel(errFailFast)
+				}
+			}
+		}()
+	}
+
+	for i := range jobs {
+		pending <- i
+	}
+	close(pending)
+	wg.Wait()
+
+	batch := &BatchResult{Jobs: results, Summary: BatchSummary{Total: len(jobs), Duration: time.Since(start)}}
+	var errs []error
+	for i, r := range results {
+		switch {
+		case r.Skipped:
+			batch.Summary.Skipped++
+		case r.Cancelled:
+			batch.Summary.Cancelled++
+		case r.Err != nil:
+			batch.Summary.Failed++
+			errs = append(errs, fmt.Errorf("trabajo %d (%s): %w", i, r.Job.Script, r.Err))
+		default:
+			batch.Summary.Succeeded++
+		}
+	}
+
+	if batch.Summary.Failed > 0 || batch.Summary.Cancelled > 0 || batch.Summary.Skipped > 0 {
+		return batch, fmt.Errorf("lote con %d fallos, %d cancelados y %d omitidos de %d trabajos: %w",
+			batch.Summary.Failed, batch.Summary.Cancelled, batch.Summary.Skipped, batch.Summary.Total, errors.Join(errs...))
+	}
+	return batch, nil
+}
+
+// runJob ejecuta un trabajo, omitiéndolo si el lote ya fue cancelado y marcándolo como
+// cancelado si FailFast lo interrumpió mientras se ejecutaba
+func (sr *ScriptRunner) runJob(ctx context.Context, job Job) JobResult {
+	if err := ctx.Err(); err != nil {
+		return JobResult{Job: job, Err: err, Skipped: true}
+	}
+
+	res, err := sr.Run(ctx, job.Options, job.Script, job.Args...)
+	cancelled := err != nil && errors.Is(err, context.Canceled) && errors.Is(context.Cause(ctx), errFailFast)
+	return JobResult{Job: job, Result: res, Err: err, Cancelled: cancelled}
+}
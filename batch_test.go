@@ -0,0 +1,122 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecuteMany(t *testing.T) {
+	projectRoot := newTempProject(t, map[string]string{
+		"job.sh": "#!/bin/bash\nsleep \"$2\"\n[ \"$1\" = fail ] && { echo \"falló $1\"; exit 2; }\necho \"hecho $1\"\n",
+	})
+	runner := NewBashRunnerWithOptions(projectRoot)
+	runner.SetWorkspaceRoot(t.TempDir())
+	ctx := context.Background()
+
+	t.Run("Resultados en orden con concurrencia limitada", func(t *testing.T) {
+		var jobs []Job
+		for i := 0; i < 8; i++ {
+			// Los primeros terminan más tarde para comprobar el orden de salida
+			delay := fmt.Sprintf("0.%d", 8-i)
+			jobs = append(jobs, Job{Script: "job", Args: []string{fmt.Sprint(i), delay}})
+		}
+
+		start := time.Now()
+		batch, err := runner.ExecuteMany(ctx, jobs, BatchOptions{Concurrency: 4})
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		// En serie tardaría 3.6s; con 4 en paralelo ronda 1.4s
+		if elapsed := time.Since(start); elapsed > 3*time.Second {
+			t.Errorf("Los trabajos no se ejecutaron en paralelo: %v", elapsed)
+		}
+
+		for i, r := range batch.Jobs {
+			if want := fmt.Sprintf("hecho %d", i); !strings.Contains(r.Result.Stdout, want) {
+				t.Errorf("Resultado %d fuera de orden: %q", i, r.Result.Stdout)
+			}
+		}
+		if batch.Summary.Succeeded != 8 || batch.Summary.Total != 8 {
+			t.Errorf("Resumen inesperado: %+v", batch.Summary)
+		}
+	})
+
+	t.Run("Continuar ante errores", func(t *testing.T) {
+		jobs := []Job{
+			{Script: "job", Args: []string{"a", "0"}},
+			{Script: "job", Args: []string{"fail", "0"}},
+			{Script: "job", Args: []string{"c", "0"}},
+		}
+
+		batch, err := runner.ExecuteMany(ctx, jobs, BatchOptions{Concurrency: 1, Policy: ContinueOnError})
+
+		var scriptErr *ScriptError
+		if !errors.As(err, &scriptErr) || scriptErr.ExitCode != 2 {
+			t.Fatalf("Se esperaba el ScriptError del trabajo fallido, se obtuvo: %v", err)
+		}
+		if batch.Summary.Succeeded != 2 || batch.Summary.Failed != 1 || batch.Summary.Skipped != 0 {
+			t.Errorf("Resumen inesperado: %+v", batch.Summary)
+		}
+		if batch.Jobs[1].Err == nil || batch.Jobs[2].Err != nil {
+			t.Errorf("Errores asignados al trabajo equivocado: %+v", batch.Jobs)
+		}
+	})
+
+	t.Run("Fallo rápido", func(t *testing.T) {
+		jobs := []Job{
+			{Script: "job", Args: []string{"fail", "0"}},
+			{Script: "job", Args: []string{"b", "0"}},
+			{Script: "job", Args: []string{"c", "0"}},
+		}
+
+		batch, err := runner.ExecuteMany(ctx, jobs, BatchOptions{Concurrency: 1, Policy: FailFast})
+		if err == nil {
+			t.Fatal("Se esperaba un error")
+		}
+		if batch.Summary.Failed != 1 || batch.Summary.Skipped != 2 {
+			t.Errorf("Resumen inesperado: %+v", batch.Summary)
+		}
+		if !batch.Jobs[2].Skipped || batch.Jobs[2].Result != nil {
+			t.Errorf("El último trabajo debió omitirse: %+v", batch.Jobs[2])
+		}
+	})
+
+	t.Run("Fallo rápido con trabajos en curso", func(t *testing.T) {
+		jobs := []Job{
+			{Script: "job", Args: []string{"b", "5"}},
+			{Script: "job", Args: []string{"fail", "0.2"}},
+			{Script: "job", Args: []string{"c", "5"}},
+			{Script: "job", Args: []string{"d", "0"}},
+		}
+
+		start := time.Now()
+		batch, err := runner.ExecuteMany(ctx, jobs, BatchOptions{Concurrency: 3, Policy: FailFast})
+		if elapsed := time.Since(start); elapsed > 4*time.Second {
+			t.Errorf("Los trabajos en curso no se cancelaron: %v", elapsed)
+		}
+		if batch.Summary.Failed != 1 || batch.Summary.Cancelled != 2 || batch.Summary.Skipped != 1 {
+			t.Errorf("Resumen inesperado: %+v", batch.Summary)
+		}
+		for _, i := range []int{0, 2} {
+			if r := batch.Jobs[i]; !r.Cancelled || r.Skipped || !errors.Is(r.Err, context.Canceled) {
+				t.Errorf("El trabajo %d debió marcarse como cancelado: %+v", i, r)
+			}
+		}
+		if r := batch.Jobs[1]; r.Cancelled || r.Result.ExitCode != 2 {
+			t.Errorf("El fallo real no debe marcarse como cancelado: %+v", r)
+		}
+
+		// El error agrupado solo incluye el fallo que provocó la cancelación
+		var scriptErr *ScriptError
+		if !errors.As(err, &scriptErr) || scriptErr.ExitCode != 2 {
+			t.Fatalf("Se esperaba el *ScriptError del trabajo fallido, se obtuvo: %v", err)
+		}
+		if errors.Is(err, context.Canceled) || strings.Contains(err.Error(), "trabajo 0") {
+			t.Errorf("El error incluye los trabajos cancelados: %v", err)
+		}
+	})
+}
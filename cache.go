@@ -0,0 +1,231 @@
+package gorunscript
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// cacheDirName es el subdirectorio de la raíz de trabajo donde se guardan los scripts extraídos
+const cacheDirName = "cache"
+
+const (
+	// defaultCacheMaxAge es el tiempo sin usarse tras el cual se elimina una versión de la caché
+	defaultCacheMaxAge = 7 * 24 * time.Hour
+
+	// staleCacheTempAge es la antigüedad a partir de la cual un directorio temporal de
+	// extracción o de descarte se considera abandonado por un proceso caído
+	staleCacheTempAge = time.Hour
+
+	cacheTempPrefix = "tmp-"      // Archivo o directorio en extracción, antes del rename que lo publica
+	discardMarker   = ".discard-" // Versión apartada para borrarla
+)
+
+// scriptFile es un script leído del filesystem de origen
+type scriptFile struct {
+	name    string
+	content []byte
+	sum     [sha256.Size]byte
+	layer   string // Capa de la que proviene el script
+}
+
+// readScriptSet lee los scripts del nivel superior de baseDir en fsys ordenados por nombre.
+// Solo se consideran scripts los archivos cuya extensión tiene intérprete registrado.
+func readScriptSet(fsys fs.FS, baseDir string, interpreters *InterpreterRegistry) ([]scriptFile, error) {
+	entries, err := fs.ReadDir(fsys, baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer directorio de scripts %s: %w", baseDir, err)
+	}
+
+	var files []scriptFile
+	for _, entry := range entries {
+		if entry.IsDir() || !interpreters.Handles(entry.Name()) {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(baseDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error al leer script %s: %w", entry.Name(), err)
+		}
+
+		files = append(files, scriptFile{
+			name:    entry.Name(),
+			content: content,
+			sum:     sha256.Sum256(content),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files, nil
+}
+
+// scriptSetKey calcula el hash que identifica una versión del conjunto de scripts
+func scriptSetKey(files []scriptFile) string {
+	h := sha256.New()
+	for _, f := range files {
+		fmt.Fprintf(h, "%s\x00%x\x00", f.name, f.sum)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// findScript busca name entre files. Si no existe con ese nombre exacto prueba a añadir
+// cada extensión registrada en orden. Si no lo encuentra devuelve el nombre con la primera
+// extensión registrada, para informar del error.
+func findScript(files []scriptFile, name string, interpreters *InterpreterRegistry) (scriptFile, bool) {
+	byName := make(map[string]scriptFile, len(files))
+	for _, f := range files {
+		byName[f.name] = f
+	}
+
+	if f, ok := byName[name]; ok {
+		return f, true
+	}
+
+	extensions := interpreters.Extensions()
+	if path.Ext(name) == "" || !interpreters.Handles(name) {
+		for _, ext := range extensions {
+			if f, ok := byName[name+ext]; ok {
+				return f, true
+			}
+		}
+	}
+
+	if !interpreters.Handles(name) && len(extensions) > 0 {
+		name += extensions[0]
+	}
+	return scriptFile{name: name}, false
+}
+
+// prepareScriptCache devuelve el directorio de la versión del catálogo con los scripts de
+// needed extraídos y verificados. Todas las ejecuciones de una misma versión comparten el
+// directorio aunque necesiten scripts distintos: cada script se extrae una sola vez, cuando
+// alguna ejecución lo necesita, y se vuelve a extraer si fue modificado o quedó incompleto.
+func prepareScriptCache(catalog, needed []scriptFile, cacheRoot string) (string, error) {
+	dir := filepath.Join(cacheRoot, scriptSetKey(catalog))
+	// Marcar la versión como usada antes de verificarla, para que pruneScriptCache no la
+	// elimine mientras se ejecuta
+	if !touchCacheEntry(dir) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("error creando directorio de caché: %w", err)
+		}
+	}
+
+	for _, f := range needed {
+		if verifyCachedScript(dir, f) {
+			continue
+		}
+		if err := extractCachedScript(dir, f); err != nil {
+			return "", err
+		}
+	}
+
+	return dir, nil
+}
+
+// extractCachedScript escribe f en dir con un archivo temporal y un rename atómico, de modo
+// que otros procesos nunca observen el script a medio escribir
+func extractCachedScript(dir string, f scriptFile) error {
+	tmp, err := os.CreateTemp(dir, cacheTempPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("error creando archivo temporal de caché: %w", err)
+	}
+	_, err = tmp.Write(f.content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0755)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("error al escribir archivo %s: %w", f.name, err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, f.name)); err != nil {
+		// Otro proceso pudo publicar el mismo script en paralelo
+		_ = os.Remove(tmp.Name())
+		if !verifyCachedScript(dir, f) {
+			return fmt.Errorf("error publicando %s en la caché de scripts: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// verifyCachedScript comprueba que dir contenga f con su contenido exacto y ejecutable
+func verifyCachedScript(dir string, f scriptFile) bool {
+	p := filepath.Join(dir, f.name)
+	info, err := os.Stat(p)
+	if err != nil || info.Size() != int64(len(f.content)) {
+		return false
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0100 == 0 {
+		return false
+	}
+
+	content, err := os.ReadFile(p)
+	return err == nil && bytes.Equal(content, f.content)
+}
+
+// discardDir aparta dir con un rename antes de borrarlo, para que nunca se observe a medio eliminar
+func discardDir(dir string) error {
+	trash := fmt.Sprintf("%s%s%d-%d", dir, discardMarker, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(dir, trash); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return os.RemoveAll(trash)
+}
+
+// touchCacheEntry actualiza la fecha de modificación de dir, que registra su último uso.
+// Devuelve false si dir no existe.
+func touchCacheEntry(dir string) bool {
+	now := time.Now()
+	return os.Chtimes(dir, now, now) == nil
+}
+
+// pruneScriptCache elimina de cacheRoot las versiones que no se usan desde hace maxAge y
+// los directorios temporales o de descarte que dejó un proceso caído. Un maxAge cero o
+// negativo solo elimina estos últimos.
+func pruneScriptCache(cacheRoot string, maxAge time.Duration) error {
+	entries, err := os.ReadDir(cacheRoot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error leyendo directorio de caché: %w", err)
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		age := now.Sub(info.ModTime())
+		dir := filepath.Join(cacheRoot, entry.Name())
+
+		switch {
+		case strings.HasPrefix(entry.Name(), cacheTempPrefix) || strings.Contains(entry.Name(), discardMarker):
+			if age > staleCacheTempAge {
+				_ = os.RemoveAll(dir)
+			}
+		case maxAge > 0 && age > maxAge:
+			_ = discardDir(dir)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package gorunscript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScriptCache(t *testing.T) {
+	cacheRoot := t.TempDir()
+	files, err := readScriptSet(bash_scripts, "bash_scripts", DefaultInterpreters())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := prepareScriptCache(files, files, cacheRoot)
+	if err != nil {
+		t.Fatalf("Error preparando caché: %v", err)
+	}
+
+	target := filepath.Join(dir, "functions.sh")
+	original, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Reutiliza la extracción existente", func(t *testing.T) {
+		before, err := os.Stat(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		again, err := prepareScriptCache(files, files, cacheRoot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again != dir {
+			t.Errorf("Se esperaba el mismo directorio de caché, se obtuvo %s y %s", dir, again)
+		}
+
+		after, err := os.Stat(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !os.SameFile(before, after) || !after.ModTime().Equal(before.ModTime()) {
+			t.Error("Los scripts se volvieron a escribir aunque la caché era válida")
+		}
+	})
+
+	t.Run("Detecta modificaciones", func(t *testing.T) {
+		if err := os.WriteFile(target, []byte("echo alterado\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := prepareScriptCache(files, files, cacheRoot); err != nil {
+			t.Fatal(err)
+		}
+
+		restored, err := os.ReadFile(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(restored) != string(original) {
+			t.Error("La caché alterada no se volvió a extraer")
+		}
+	})
+
+	t.Run("Detecta extracción parcial", func(t *testing.T) {
+		if err := os.Remove(target); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := prepareScriptCache(files, files, cacheRoot); err != nil {
+			t.Fatal(err)
+		}
+
+		if !fileExists(target) {
+			t.Error("No se restauró el script faltante")
+		}
+	})
+}
+
+func TestScriptCacheSharedByClosures(t *testing.T) {
+	cacheRoot := t.TempDir()
+	catalog, err := readScriptSet(bash_scripts, "bash_scripts", DefaultInterpreters())
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]scriptFile)
+	for _, f := range catalog {
+		byName[f.name] = f
+	}
+
+	first, err := prepareScriptCache(catalog, []scriptFile{byName["functions.sh"]}, cacheRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := prepareScriptCache(catalog, []scriptFile{byName["functions.sh"], byName["tag.sh"]}, cacheRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second {
+		t.Errorf("Se esperaba un único directorio por versión del catálogo: %s y %s", first, second)
+	}
+	entries, err := os.ReadDir(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if len(names) != 2 || names[0] != "functions.sh" || names[1] != "tag.sh" {
+		t.Errorf("Solo debían extraerse los scripts necesarios: %q", names)
+	}
+	if dirs, _ := os.ReadDir(cacheRoot); len(dirs) != 1 {
+		t.Errorf("Se esperaba una sola entrada en la caché, hay %d", len(dirs))
+	}
+}
+
+func TestPruneScriptCache(t *testing.T) {
+	cacheRoot := t.TempDir()
+	files, err := readScriptSet(bash_scripts, "bash_scripts", DefaultInterpreters())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	used, err := prepareScriptCache(files, files, cacheRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unused, err := prepareScriptCache(files[:1], files[:1], cacheRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	stale := []string{
+		filepath.Join(cacheRoot, cacheTempPrefix+"123"),
+		filepath.Join(cacheRoot, "abc"+discardMarker+"1-2"),
+	}
+	fresh := filepath.Join(cacheRoot, cacheTempPrefix+"456")
+	for _, dir := range append(stale, fresh) {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, dir := range append(stale, used, unused) {
+		if err := os.Chtimes(dir, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Un acierto de caché renueva la fecha de uso
+	if _, err := prepareScriptCache(files, files, cacheRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pruneScriptCache(cacheRoot, 7*24*time.Hour); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+
+	if !fileExists(used) || !fileExists(fresh) {
+		t.Error("Se eliminaron entradas en uso o extracciones en curso")
+	}
+	for _, dir := range append(stale, unused) {
+		if fileExists(dir) {
+			t.Errorf("No se eliminó %s", filepath.Base(dir))
+		}
+	}
+
+	t.Run("CleanWorkspaces", func(t *testing.T) {
+		root := t.TempDir()
+		runner := NewBashRunner()
+		runner.SetWorkspaceRoot(root)
+		if _, _, err := runner.ExecuteScript("test-script"); err != nil {
+			t.Fatal(err)
+		}
+
+		entries, _ := os.ReadDir(filepath.Join(root, cacheDirName))
+		if len(entries) == 0 {
+			t.Fatal("Se esperaba una entrada en la caché")
+		}
+		for _, e := range entries {
+			dir := filepath.Join(root, cacheDirName, e.Name())
+			if err := os.Chtimes(dir, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := runner.CleanWorkspaces(); err != nil {
+			t.Fatal(err)
+		}
+		if entries, _ := os.ReadDir(filepath.Join(root, cacheDirName)); len(entries) != 0 {
+			t.Errorf("Se esperaba la caché vacía, quedan %d entradas", len(entries))
+		}
+	})
+}
+
+// BenchmarkFullExtraction mide la extracción completa con chmod que se hacía en cada llamada
+func BenchmarkFullExtraction(b *testing.B) {
+	root := b.TempDir()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		dir, err := os.MkdirTemp(root, "extract-")
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		files, err := readScriptSet(bash_scripts, "bash_scripts", DefaultInterpreters())
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, f := range files {
+			p := filepath.Join(dir, f.name)
+			if err := os.WriteFile(p, f.content, 0644); err != nil {
+				b.Fatal(err)
+			}
+			if err := os.Chmod(p, 0755); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		b.StopTimer()
+		_ = os.RemoveAll(dir)
+		b.StartTimer()
+	}
+}
+
+// BenchmarkPrepareScriptCache mide la preparación reutilizando la caché ya verificada
+func BenchmarkPrepareScriptCache(b *testing.B) {
+	cacheRoot := b.TempDir()
+	files, err := readScriptSet(bash_scripts, "bash_scripts", DefaultInterpreters())
+	if err != nil {
+		b.Fatal(err)
+	}
+	if _, err := prepareScriptCache(files, files, cacheRoot); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		// Cada llamada vuelve a leer y hashear el origen como hace Run
+		files, err := readScriptSet(bash_scripts, "bash_scripts", DefaultInterpreters())
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := prepareScriptCache(files, files, cacheRoot); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
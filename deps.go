@@ -0,0 +1,158 @@
+package gorunscript
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// refKind clasifica cómo un script hace referencia a otro
+type refKind int
+
+const (
+	refSource  refKind = iota // `source x.sh` o `. x.sh`: obligatoria
+	refRun                    // `bash x.sh` o `sh x.sh`: obligatoria
+	refCommand                // `x.sh args` resuelto por PATH: opcional
+	refCheck                  // `command -v x.sh`: comprueba si existe, opcional
+)
+
+// scriptRef es una referencia estática a otro script encontrada en el código fuente
+type scriptRef struct {
+	name string
+	line int
+	kind refKind
+}
+
+// required indica si la falta del script referenciado impide ejecutar al que lo usa
+func (r scriptRef) required() bool {
+	return r.kind == refSource || r.kind == refRun
+}
+
+// commandSeparators divide una línea en los comandos simples que la componen
+var commandSeparators = regexp.MustCompile("&&|\\|\\||[;|&(){}`]|\\$\\(")
+
+// commandPrefixes son las palabras que pueden preceder al nombre de un comando
+var commandPrefixes = map[string]bool{
+	"if": true, "then": true, "else": true, "elif": true, "do": true,
+	"while": true, "until": true, "!": true, "exec": true, "time": true,
+}
+
+// parseScriptRefs extrae las referencias estáticas de content a otros scripts manejados
+// por interpreters. Las líneas de comentario y las rutas dinámicas (con `$` o `/`) se
+// ignoran: no pueden resolverse sin ejecutar el script.
+func parseScriptRefs(content []byte, interpreters *InterpreterRegistry) []scriptRef {
+	var refs []scriptRef
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		for _, segment := range commandSeparators.Split(line, -1) {
+			fields := strings.Fields(segment)
+			for len(fields) > 0 && commandPrefixes[fields[0]] {
+				fields = fields[1:]
+			}
+			if len(fields) == 0 {
+				continue
+			}
+
+			if ref, ok := parseCommandRef(fields, interpreters); ok {
+				ref.line = n
+				refs = append(refs, ref)
+			}
+		}
+	}
+
+	return refs
+}
+
+// parseCommandRef interpreta un comando simple ya dividido en palabras
+func parseCommandRef(fields []string, interpreters *InterpreterRegistry) (scriptRef, bool) {
+	kind := refCommand
+	target := fields[0]
+
+	switch fields[0] {
+	case "source", ".":
+		kind = refSource
+	case "bash", "sh":
+		kind = refRun
+	case "command":
+		// `command -v x.sh` comprueba si x.sh está en el PATH
+		if len(fields) < 3 || fields[1] != "-v" {
+			return scriptRef{}, false
+		}
+		kind = refCheck
+		target = fields[2]
+	}
+
+	if kind == refSource || kind == refRun {
+		target = ""
+		for _, field := range fields[1:] {
+			if field == "-c" {
+				return scriptRef{}, false
+			}
+			if !strings.HasPrefix(field, "-") {
+				target = field
+				break
+			}
+		}
+	}
+
+	target = strings.Trim(target, `"'`)
+	if target == "" || strings.ContainsAny(target, "$/\\") || !interpreters.Handles(target) {
+		return scriptRef{}, false
+	}
+
+	return scriptRef{name: target, kind: kind}, true
+}
+
+// dependencyClosure devuelve los scripts de catalog que necesitan roots: los propios roots
+// que pertenecen al catálogo y todos los que referencian, de forma transitiva, ordenados por
+// nombre. Una referencia obligatoria a un script que no existe produce un
+// *MissingDependencyError; las invocaciones por PATH se incluyen solo si existen.
+func dependencyClosure(catalog, roots []scriptFile, interpreters *InterpreterRegistry) ([]scriptFile, error) {
+	byName := make(map[string]scriptFile, len(catalog))
+	for _, f := range catalog {
+		byName[f.name] = f
+	}
+
+	included := make(map[string]bool)
+	queue := append([]scriptFile(nil), roots...)
+	for _, root := range roots {
+		if f, ok := byName[root.name]; ok && f.sum == root.sum {
+			included[root.name] = true
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, ref := range parseScriptRefs(current.content, interpreters) {
+			dep, ok := byName[ref.name]
+			if !ok {
+				if ref.required() {
+					return nil, &MissingDependencyError{Script: current.name, Dependency: ref.name, Line: ref.line}
+				}
+				continue
+			}
+			if !included[dep.name] {
+				included[dep.name] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	var closure []scriptFile
+	for _, f := range catalog {
+		if included[f.name] {
+			closure = append(closure, f)
+		}
+	}
+	return closure, nil
+}
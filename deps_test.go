@@ -0,0 +1,153 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseScriptRefs(t *testing.T) {
+	source := `#!/bin/bash
+# source comentado.sh
+source functions.sh
+. "git-utils.sh"
+if ! command -v repo-rename.sh >/dev/null 2>&1; then
+    echo "falta"
+fi
+bash -e pu.sh "mensaje"; sh tag.sh
+source "$HOME/.bashrc"
+. ./local.sh
+bash -c "echo hola.sh"
+output=$(go-mod-init.sh --quiet)
+echo config.txt
+`
+	want := []scriptRef{
+		{name: "functions.sh", line: 3, kind: refSource},
+		{name: "git-utils.sh", line: 4, kind: refSource},
+		{name: "repo-rename.sh", line: 5, kind: refCheck},
+		{name: "pu.sh", line: 8, kind: refRun},
+		{name: "tag.sh", line: 8, kind: refRun},
+		{name: "go-mod-init.sh", line: 12, kind: refCommand},
+	}
+
+	got := parseScriptRefs([]byte(source), DefaultInterpreters())
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Referencias inesperadas:\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestMinimalExtraction(t *testing.T) {
+	t.Run("Solo se extrae el cierre de dependencias", func(t *testing.T) {
+		runner := NewBashRunner()
+		runner.SetWorkspaceRoot(t.TempDir())
+
+		res, err := runner.Run(context.Background(), ExecOptions{}, "test-script")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+
+		want := []string{"functions.sh", "test-script.sh"}
+		if got := dirNames(t, filepath.Dir(res.ScriptPath)); !reflect.DeepEqual(got, want) {
+			t.Errorf("Se esperaba extraer %v, se extrajo %v", want, got)
+		}
+	})
+
+	t.Run("Dependencias transitivas", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"main.sh":   {Data: []byte("#!/bin/bash\nsource lib.sh\nsaludo\n")},
+			"lib.sh":    {Data: []byte("saludo() { bash helper.sh; }\n")},
+			"helper.sh": {Data: []byte("echo hola desde helper\n")},
+			"otro.sh":   {Data: []byte("echo otro\n")},
+		}
+		runner := NewScriptRunner(fsys, ".", "bash")
+		runner.SetWorkspaceRoot(t.TempDir())
+
+		res, err := runner.Run(context.Background(), ExecOptions{}, "main")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+		if res.Stdout != "hola desde helper\n" {
+			t.Errorf("Salida inesperada: %q", res.Stdout)
+		}
+
+		want := []string{"helper.sh", "lib.sh", "main.sh"}
+		if got := dirNames(t, filepath.Dir(res.ScriptPath)); !reflect.DeepEqual(got, want) {
+			t.Errorf("Se esperaba extraer %v, se extrajo %v", want, got)
+		}
+	})
+
+	t.Run("Dependencia inexistente antes de ejecutar", func(t *testing.T) {
+		marker := filepath.Join(t.TempDir(), "ejecutado")
+		fsys := fstest.MapFS{
+			"main.sh": {Data: []byte("#!/bin/bash\ntouch \"$1\"\nsource lib.sh\n")},
+			"lib.sh":  {Data: []byte("echo lib\nbash falta.sh\n")},
+		}
+		runner := NewScriptRunner(fsys, ".", "bash")
+		runner.SetWorkspaceRoot(t.TempDir())
+
+		_, err := runner.Run(context.Background(), ExecOptions{}, "main", marker)
+		if !errors.Is(err, ErrMissingDependency) {
+			t.Fatalf("Se esperaba ErrMissingDependency, se obtuvo: %v", err)
+		}
+
+		var depErr *MissingDependencyError
+		if !errors.As(err, &depErr) {
+			t.Fatalf("Se esperaba *MissingDependencyError, se obtuvo %T", err)
+		}
+		if depErr.Script != "lib.sh" || depErr.Dependency != "falta.sh" || depErr.Line != 2 {
+			t.Errorf("Detalle inesperado: %+v", depErr)
+		}
+		if fileExists(marker) {
+			t.Error("El script no debía ejecutarse con una dependencia faltante")
+		}
+	})
+
+	t.Run("Las invocaciones por PATH inexistentes no son obligatorias", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"main.sh": {Data: []byte("#!/bin/bash\nif command -v opcional.sh >/dev/null; then opcional.sh; fi\necho fin\n")},
+		}
+		runner := NewScriptRunner(fsys, ".", "bash")
+		runner.SetWorkspaceRoot(t.TempDir())
+
+		res, err := runner.Run(context.Background(), ExecOptions{}, "main")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+	})
+
+	t.Run("Scripts en línea", func(t *testing.T) {
+		runner := NewBashRunner()
+		runner.SetWorkspaceRoot(t.TempDir())
+
+		res, err := runner.RunInline(context.Background(), ExecOptions{}, "source functions.sh\ncommand -v functions.sh\n")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+
+		want := []string{"functions.sh"}
+		if got := dirNames(t, filepath.Dir(strings.TrimSpace(res.Stdout))); !reflect.DeepEqual(got, want) {
+			t.Errorf("Se esperaba extraer %v, se extrajo %v", want, got)
+		}
+	})
+}
+
+// dirNames devuelve los nombres ordenados de las entradas de dir
+func dirNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,115 @@
+package gorunscript
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cdvelop/gorunscript/internal/shim"
+)
+
+// defaultDryRunCommands son los comandos externos que DryRun sustituye si no se indican otros
+var defaultDryRunCommands = []string{"git", "gh", "go", "sudo", "sed"}
+
+// CommandResponse es la respuesta preparada de un comando sustituido en un dry-run
+type CommandResponse struct {
+	// Args es el prefijo de argumentos al que se aplica la respuesta, p. ej. {"tag", "-l"};
+	// vacío coincide con cualquier invocación. Gana la primera respuesta que coincide.
+	Args []string
+
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// PlannedCommand es una invocación de un comando externo registrada durante un dry-run
+type PlannedCommand struct {
+	Command string
+	Args    []string
+}
+
+// String devuelve la invocación como línea de comandos legible
+func (c PlannedCommand) String() string {
+	return shim.Call{Command: c.Command, Args: c.Args}.String()
+}
+
+// DryRunOptions configura una ejecución en modo dry-run
+type DryRunOptions struct {
+	ExecOptions
+
+	// Commands son los comandos que se sustituyen; nil usa git, gh, go, sudo y sed.
+	// Los comandos con respuestas en Responses se sustituyen siempre.
+	Commands []string
+
+	// Responses son las respuestas de cada comando. Un comando sin respuesta que coincida
+	// no escribe nada y termina con código 0.
+	Responses map[string][]CommandResponse
+}
+
+// DryRunResult es el resultado de un dry-run
+type DryRunResult struct {
+	*Result
+
+	// Plan son las invocaciones de los comandos sustituidos, en el orden en que ocurrieron
+	Plan []PlannedCommand
+}
+
+// DryRun ejecuta un script con comandos falsos delante de los reales en el PATH, para ver
+// qué haría sin que haga nada. Cada comando sustituido registra sus argumentos en el plan
+// y responde con la salida preparada en opts.Responses en lugar de ejecutarse.
+//
+// El script sí se ejecuta: los comandos no sustituidos (rm, mkdir, curl...) actúan de
+// verdad, por lo que conviene dejar opts.Dir vacío para que trabaje en el espacio de
+// trabajo aislado. Tampoco se sustituyen las rutas absolutas como /usr/bin/git.
+func (sr *ScriptRunner) DryRun(ctx context.Context, opts DryRunOptions, scriptName string, args ...string) (*DryRunResult, error) {
+	result := &DryRunResult{Result: sr.newResult(scriptName, args)}
+
+	dir, err := os.MkdirTemp("", "gorunscript-dryrun-")
+	if err != nil {
+		return result, fmt.Errorf("error creando directorio de dry-run: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	commands := opts.Commands
+	if commands == nil {
+		commands = defaultDryRunCommands
+	}
+	responses := make(map[string][]shim.Response)
+	for _, command := range commands {
+		responses[command] = nil
+	}
+	for command, rs := range opts.Responses {
+		converted := responses[command]
+		for _, r := range rs {
+			converted = append(converted, shim.Response(r))
+		}
+		responses[command] = converted
+	}
+
+	logPath := filepath.Join(dir, "plan.log")
+	binDir := filepath.Join(dir, "bin")
+	if err := os.Mkdir(binDir, 0755); err != nil {
+		return result, fmt.Errorf("error creando directorio de dry-run: %w", err)
+	}
+	for command, rs := range responses {
+		if err := shim.Write(binDir, logPath, command, rs); err != nil {
+			return result, err
+		}
+	}
+
+	execOpts := opts.ExecOptions
+	execOpts.extraPath = append(append([]string(nil), execOpts.extraPath...), binDir)
+	res, runErr := sr.Run(ctx, execOpts, scriptName, args...)
+	result.Result = res
+
+	calls, err := shim.ReadLog(logPath)
+	if err != nil {
+		return result, err
+	}
+	for _, call := range calls {
+		result.Plan = append(result.Plan, PlannedCommand(call))
+	}
+
+	return result, runErr
+}
@@ -0,0 +1,75 @@
+package gorunscript
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Run("Plan de un script destructivo", func(t *testing.T) {
+		runner := NewBashRunner()
+		runner.SetWorkspaceRoot(t.TempDir())
+
+		res, err := runner.DryRun(context.Background(), DryRunOptions{}, "deltag", "v0.1.0", "v0.2.0")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+
+		want := []string{
+			"git tag -d v0.1.0",
+			"git push origin --delete v0.1.0",
+			"git tag -d v0.2.0",
+			"git push origin --delete v0.2.0",
+		}
+		var got []string
+		for _, c := range res.Plan {
+			got = append(got, c.String())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Plan inesperado:\n got: %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("Respuestas preparadas", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"release.sh": {Data: []byte("#!/bin/bash\n" +
+				"last=$(git describe --tags)\n" +
+				"echo \"última: $last\"\n" +
+				"if ! gh release view \"$last\" >/dev/null; then\n" +
+				"  gh release create \"$last\" --notes \"nota con espacios\"\n" +
+				"fi\n" +
+				"mycmd --version\n")},
+		}
+		runner := NewScriptRunner(fsys, ".", "bash")
+		runner.SetWorkspaceRoot(t.TempDir())
+
+		opts := DryRunOptions{
+			Commands: []string{"gh"},
+			Responses: map[string][]CommandResponse{
+				"git":   {{Args: []string{"describe"}, Stdout: "v1.2.3\n"}},
+				"gh":    {{Args: []string{"release", "view"}, Stderr: "release not found\n", ExitCode: 1}},
+				"mycmd": nil,
+			},
+		}
+		res, err := runner.DryRun(context.Background(), opts, "release")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+
+		if !strings.Contains(res.Stdout, "última: v1.2.3") {
+			t.Errorf("No se usó la respuesta preparada de git: %q", res.Stdout)
+		}
+		want := []PlannedCommand{
+			{Command: "git", Args: []string{"describe", "--tags"}},
+			{Command: "gh", Args: []string{"release", "view", "v1.2.3"}},
+			{Command: "gh", Args: []string{"release", "create", "v1.2.3", "--notes", "nota con espacios"}},
+			{Command: "mycmd", Args: []string{"--version"}},
+		}
+		if !reflect.DeepEqual(res.Plan, want) {
+			t.Errorf("Plan inesperado:\n got: %q\nwant: %q", res.Plan, want)
+		}
+	})
+}
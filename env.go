@@ -0,0 +1,89 @@
+package gorunscript
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// buildEnv construye el entorno del script: el heredado (o solo el permitido en modo
+// limpio), el directorio de scripts, los de extraPath y los del runner al inicio de PATH,
+// LANG=C y las variables adicionales
+func (o ExecOptions) buildEnv(scriptsDir string, runnerPath []string) []string {
+	base := os.Environ()
+	if o.CleanEnv {
+		base = filterEnv(base, o.EnvAllowlist)
+	}
+
+	dirs := append([]string{scriptsDir}, o.extraPath...)
+	dirs = append(dirs, runnerPath...)
+	env := prependPath(base, strings.Join(dirs, string(os.PathListSeparator)))
+	env = append(env, "LANG=C")
+	// exec.Cmd usa el último valor de cada clave, así que Env puede sobrescribir lo anterior
+	return append(env, o.Env...)
+}
+
+// filterEnv conserva PATH y las variables cuyo nombre está en allowlist
+func filterEnv(env []string, allowlist []string) []string {
+	allowed := make(map[string]bool, len(allowlist))
+	for _, name := range allowlist {
+		allowed[envKey(name)] = true
+	}
+
+	var result []string
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		if isPathKey(key) || allowed[envKey(key)] || essentialEnv(key) {
+			result = append(result, kv)
+		}
+	}
+	return result
+}
+
+// envKey normaliza el nombre de una variable; en Windows no distingue mayúsculas
+func envKey(key string) string {
+	if runtime.GOOS == "windows" {
+		return strings.ToUpper(key)
+	}
+	return key
+}
+
+// essentialEnv indica variables sin las cuales los procesos de Windows no arrancan
+func essentialEnv(key string) bool {
+	return runtime.GOOS == "windows" && strings.EqualFold(key, "SYSTEMROOT")
+}
+
+// prependPath devuelve una copia de env con dir al inicio de PATH
+func prependPath(env []string, dir string) []string {
+	result := make([]string, 0, len(env)+1)
+	found := false
+
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		if !found && isPathKey(key) {
+			found = true
+			if value != "" {
+				value = dir + string(os.PathListSeparator) + value
+			} else {
+				value = dir
+			}
+			result = append(result, key+"="+value)
+			continue
+		}
+		result = append(result, kv)
+	}
+
+	if !found {
+		result = append(result, "PATH="+dir)
+	}
+
+	return result
+}
+
+// isPathKey indica si key es la variable PATH; en Windows no distingue mayúsculas
+func isPathKey(key string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(key, "PATH")
+	}
+	return key == "PATH"
+}
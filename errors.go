@@ -0,0 +1,182 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Errores centinela para distinguir el tipo de fallo con errors.Is
+var (
+	// ErrScriptNotFound indica que el script solicitado no existe entre los disponibles
+	ErrScriptNotFound = errors.New("script no encontrado")
+
+	// ErrInterpreterNotFound indica que el intérprete configurado no está instalado o no es ejecutable
+	ErrInterpreterNotFound = errors.New("intérprete no encontrado")
+
+	// ErrExtraction indica un fallo al extraer o copiar los scripts a disco
+	ErrExtraction = errors.New("error extrayendo scripts")
+
+	// ErrMissingDependency indica que un script referencia con source o bash a otro que no existe
+	ErrMissingDependency = errors.New("dependencia no encontrada")
+
+	// ErrValidation indica que la validación previa a la ejecución encontró errores de sintaxis
+	ErrValidation = errors.New("error de sintaxis en los scripts")
+
+	// ErrFunctionNotFound indica que la función solicitada no está definida en el script
+	ErrFunctionNotFound = errors.New("función no encontrada")
+
+	// ErrPTYUnsupported indica que el modo PTY no está disponible en este sistema operativo
+	ErrPTYUnsupported = errors.New("modo PTY no disponible en este sistema")
+
+	// ErrUnexpectedPrompt indica que el script se detuvo en una pregunta que ninguna regla de Expect responde
+	ErrUnexpectedPrompt = errors.New("pregunta inesperada")
+
+	// ErrExpectTimeout indica que una pregunta obligatoria de Expect no apareció a tiempo
+	ErrExpectTimeout = errors.New("pregunta esperada no recibida")
+
+	// ErrOutputs indica que las salidas publicadas por el script no se pudieron leer o decodificar
+	ErrOutputs = errors.New("error en las salidas del script")
+)
+
+// ScriptNotFoundError se devuelve cuando el script solicitado no existe.
+// Satisface errors.Is(err, ErrScriptNotFound).
+type ScriptNotFoundError struct {
+	Script    string   // Nombre solicitado, con extensión
+	Available []string // Nombres de los scripts disponibles
+}
+
+func (e *ScriptNotFoundError) Error() string {
+	return fmt.Sprintf("error: el script '%s' no existe. Archivos disponibles: %v", e.Script, e.Available)
+}
+
+// Is permite comparar con ErrScriptNotFound
+func (e *ScriptNotFoundError) Is(target error) bool {
+	return target == ErrScriptNotFound
+}
+
+// newScriptNotFoundError crea el error de un script que no está en catalog
+func newScriptNotFoundError(name string, catalog []scriptFile) *ScriptNotFoundError {
+	available := make([]string, len(catalog))
+	for i, f := range catalog {
+		available[i] = f.name
+	}
+	return &ScriptNotFoundError{Script: name, Available: available}
+}
+
+// FunctionNotFoundError se devuelve cuando la función solicitada no está definida en el
+// script ni en los archivos que carga. Satisface errors.Is(err, ErrFunctionNotFound).
+type FunctionNotFoundError struct {
+	Script    string   // Nombre del script con extensión
+	Function  string   // Función solicitada
+	Available []string // Funciones definidas al cargar el script, ordenadas
+}
+
+func (e *FunctionNotFoundError) Error() string {
+	return fmt.Sprintf("error: la función '%s' no está definida en %s. Funciones disponibles: %v", e.Function, e.Script, e.Available)
+}
+
+// Is permite comparar con ErrFunctionNotFound
+func (e *FunctionNotFoundError) Is(target error) bool {
+	return target == ErrFunctionNotFound
+}
+
+// UnexpectedPromptError termina un script que quedó esperando una respuesta que ninguna
+// regla de Expect prevé. Satisface errors.Is(err, ErrUnexpectedPrompt).
+type UnexpectedPromptError struct {
+	Prompt string        // Línea sin terminar en la que se detuvo el script
+	Idle   time.Duration // Tiempo que el script estuvo en silencio
+}
+
+func (e *UnexpectedPromptError) Error() string {
+	return fmt.Sprintf("error: el script espera una respuesta no prevista tras %v en silencio: %q", e.Idle, e.Prompt)
+}
+
+// Is permite comparar con ErrUnexpectedPrompt
+func (e *UnexpectedPromptError) Is(target error) bool {
+	return target == ErrUnexpectedPrompt
+}
+
+// ExpectTimeoutError termina un script cuando una pregunta obligatoria no apareció en el
+// plazo de su regla. Satisface errors.Is(err, ErrExpectTimeout).
+type ExpectTimeoutError struct {
+	Pattern string        // Patrón de la regla
+	Timeout time.Duration // Plazo de la regla
+	Output  string        // Salida recibida desde la última respuesta
+}
+
+func (e *ExpectTimeoutError) Error() string {
+	return fmt.Sprintf("error: la pregunta %q no apareció en %v; última salida: %q", e.Pattern, e.Timeout, lastLines(e.Output, 3))
+}
+
+// Is permite comparar con ErrExpectTimeout
+func (e *ExpectTimeoutError) Is(target error) bool {
+	return target == ErrExpectTimeout
+}
+
+// lastLines devuelve las últimas n líneas no vacías de text
+func lastLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MissingDependencyError se devuelve antes de ejecutar cuando un script necesita otro que
+// no está en el catálogo. Satisface errors.Is(err, ErrMissingDependency).
+type MissingDependencyError struct {
+	Script     string // Script que contiene la referencia
+	Dependency string // Script referenciado que no existe
+	Line       int    // Línea de la referencia, empezando en 1
+}
+
+func (e *MissingDependencyError) Error() string {
+	return fmt.Sprintf("error: %s:%d requiere '%s', que no existe", e.Script, e.Line, e.Dependency)
+}
+
+// Is permite comparar con ErrMissingDependency
+func (e *MissingDependencyError) Is(target error) bool {
+	return target == ErrMissingDependency
+}
+
+// ScriptError se devuelve cuando el script se ejecutó pero no terminó con éxito: código de
+// salida distinto de cero, terminación por señal o interrupción por el contexto.
+// Unwrap devuelve la causa (*exec.ExitError o el error del contexto).
+type ScriptError struct {
+	Script   string    // Nombre del script con extensión
+	ExitCode int       // Código de salida; -1 si terminó por una señal
+	Signal   os.Signal // Señal que terminó el proceso, nil si salió normalmente
+	Output   string    // stdout y stderr combinados
+	Err      error     // Causa subyacente
+}
+
+func (e *ScriptError) Error() string {
+	if e.Signal != nil {
+		return fmt.Sprintf("error ejecutando script %s (código %d, señal %v): %v", e.Script, e.ExitCode, e.Signal, e.Err)
+	}
+	return fmt.Sprintf("error ejecutando script %s (código %d): %v", e.Script, e.ExitCode, e.Err)
+}
+
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout indica si la ejecución se interrumpió por el plazo del contexto
+func (e *ScriptError) Timeout() bool {
+	return errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+// newScriptError construye el error de una ejecución fallida a partir de su resultado
+func newScriptError(res *Result, cause error) *ScriptError {
+	return &ScriptError{
+		Script:   res.Script,
+		ExitCode: res.ExitCode,
+		Signal:   res.Signal,
+		Output:   res.Output,
+		Err:      cause,
+	}
+}
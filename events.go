@@ -0,0 +1,135 @@
+package gorunscript
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventFDEnv es la variable de entorno con el descriptor por el que el script envía
+// eventos al runner. Los auxiliares de events.sh no hacen nada si no está definida.
+const EventFDEnv = "GORUNSCRIPT_EVENT_FD"
+
+// eventFD es el descriptor de eventos del script: el primero tras stdin, stdout y stderr
+const eventFD = 3
+
+// eventDrainTimeout es cuánto se espera, tras terminar el script, a que los procesos
+// hijos que heredaron el descriptor de eventos lo cierren
+const eventDrainTimeout = 500 * time.Millisecond
+
+// EventKind es el tipo de un evento enviado por un script
+type EventKind string
+
+const (
+	EventStep     EventKind = "step"     // emit_step "mensaje": empieza un paso
+	EventProgress EventKind = "progress" // emit_progress actual total "mensaje"
+	EventWarning  EventKind = "warning"  // emit_warning "mensaje"
+	EventResult   EventKind = "result"   // emit_result estado "mensaje": resultado final
+)
+
+// Event es un registro enviado por el script mientras se ejecuta. Cada registro es una
+// línea con campos separados por tabuladores, el primero el tipo de evento.
+type Event struct {
+	Kind    EventKind
+	Message string
+	Current int    // Avance de EventProgress
+	Total   int    // Total de EventProgress, 0 si se desconoce
+	Status  string // Estado de EventResult, p. ej. "ok" o "error"
+
+	Fields []string  // Campos tras el tipo, sin interpretar; útil para tipos propios
+	Time   time.Time // Momento en que el runner recibió el evento
+}
+
+// parseEvent decodifica una línea del protocolo de eventos
+func parseEvent(line string) Event {
+	fields := strings.Split(line, "\t")
+	ev := Event{Kind: EventKind(fields[0]), Fields: fields[1:], Time: time.Now()}
+	field := func(i int) string {
+		if i < len(ev.Fields) {
+			return ev.Fields[i]
+		}
+		return ""
+	}
+
+	switch ev.Kind {
+	case EventProgress:
+		ev.Current, _ = strconv.Atoi(field(0))
+		ev.Total, _ = strconv.Atoi(field(1))
+		ev.Message = field(2)
+	case EventResult:
+		ev.Status = field(0)
+		ev.Message = field(1)
+	default:
+		ev.Message = field(0)
+	}
+	return ev
+}
+
+// eventReader recibe los eventos del script por una tubería heredada como descriptor 3
+type eventReader struct {
+	r, w    *os.File
+	onEvent func(Event)
+	done    chan struct{}
+	events  []Event
+}
+
+// attachEvents conecta la tubería de eventos a cmd. Devuelve nil si la plataforma no
+// permite heredar descriptores adicionales.
+func attachEvents(cmd *exec.Cmd, onEvent func(Event)) (*eventReader, error) {
+	if !eventFDSupported {
+		return nil, nil
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.ExtraFiles = append(cmd.ExtraFiles, w)
+	cmd.Env = append(cmd.Env, EventFDEnv+"="+strconv.Itoa(eventFD+len(cmd.ExtraFiles)-1))
+
+	return &eventReader{r: r, w: w, onEvent: onEvent, done: make(chan struct{})}, nil
+}
+
+// start empieza a leer eventos; debe llamarse una vez iniciado el proceso
+func (er *eventReader) start() {
+	// El extremo de escritura solo debe quedar abierto en el proceso hijo
+	_ = er.w.Close()
+
+	go func() {
+		defer close(er.done)
+		scanner := bufio.NewScanner(er.r)
+		for scanner.Scan() {
+			line := strings.TrimRight(scanner.Text(), "\r")
+			if line == "" {
+				continue
+			}
+			ev := parseEvent(line)
+			er.events = append(er.events, ev)
+			if er.onEvent != nil {
+				er.onEvent(ev)
+			}
+		}
+	}()
+}
+
+// abort libera la tubería cuando el proceso no llegó a iniciarse
+func (er *eventReader) abort() {
+	_ = er.w.Close()
+	_ = er.r.Close()
+}
+
+// finish espera a que se cierre la tubería y devuelve los eventos recibidos. Si algún
+// proceso en segundo plano la mantiene abierta, se deja de leer tras eventDrainTimeout.
+func (er *eventReader) finish() []Event {
+	select {
+	case <-er.done:
+	case <-time.After(eventDrainTimeout):
+		_ = er.r.Close()
+		<-er.done
+	}
+	_ = er.r.Close()
+	return er.events
+}
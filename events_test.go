@@ -0,0 +1,87 @@
+//go:build !windows
+
+package gorunscript
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScriptEvents(t *testing.T) {
+	projectRoot := newTempProject(t, map[string]string{
+		"eventos.sh": "#!/bin/bash\nsource events.sh\n" +
+			"emit_step \"Preparando\"\necho salida normal\n" +
+			"emit_progress 1 2 \"mitad\"\nsleep 0.2\n" +
+			"emit_warning $'con\\ttabulador\\ny salto'\necho error normal >&2\n" +
+			"emit_progress 2 2 \"fin\"\nemit_result ok \"v0.0.2\"\n",
+	})
+	runner := NewBashRunnerWithOptions(projectRoot)
+	runner.SetWorkspaceRoot(t.TempDir())
+
+	var mu sync.Mutex
+	var live []Event
+	var firstAt time.Time
+	opts := ExecOptions{OnEvent: func(ev Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(live) == 0 {
+			firstAt = time.Now()
+		}
+		live = append(live, ev)
+	}}
+
+	res, err := runner.Run(context.Background(), opts, "eventos")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+	}
+	finishedAt := time.Now()
+
+	if res.Stdout != "salida normal\n" || res.Stderr != "error normal\n" {
+		t.Errorf("Los eventos no deben mezclarse con la salida: stdout %q, stderr %q", res.Stdout, res.Stderr)
+	}
+
+	var got []string
+	for _, ev := range res.Events {
+		got = append(got, strings.Join(append([]string{string(ev.Kind)}, ev.Fields...), "|"))
+	}
+	want := []string{"step|Preparando", "progress|1|2|mitad", "warning|con tabulador y salto", "progress|2|2|fin", "result|ok|v0.0.2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Eventos inesperados:\n got: %q\nwant: %q", got, want)
+	}
+
+	progress := res.Events[1]
+	if progress.Kind != EventProgress || progress.Current != 1 || progress.Total != 2 || progress.Message != "mitad" {
+		t.Errorf("Progreso mal decodificado: %+v", progress)
+	}
+	result := res.Events[4]
+	if result.Kind != EventResult || result.Status != "ok" || result.Message != "v0.0.2" {
+		t.Errorf("Resultado mal decodificado: %+v", result)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(live) != len(res.Events) {
+		t.Errorf("OnEvent recibió %d eventos, se esperaban %d", len(live), len(res.Events))
+	}
+	if finishedAt.Sub(firstAt) < 150*time.Millisecond {
+		t.Error("Los eventos deben entregarse mientras el script se ejecuta, no al final")
+	}
+}
+
+func TestScriptEventsWithoutHelpers(t *testing.T) {
+	runner := NewBashRunner()
+	runner.SetWorkspaceRoot(t.TempDir())
+
+	// Sin eventos el resultado no los incluye y los auxiliares fuera de gorunscript no hacen nada
+	res, err := runner.RunInline(context.Background(), ExecOptions{}, "source events.sh\nunset GORUNSCRIPT_EVENT_FD\nemit_step paso\necho ok\n")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+	}
+	if len(res.Events) != 0 || res.Output != "ok\n" {
+		t.Errorf("Resultado inesperado: eventos %v, salida %q", res.Events, res.Output)
+	}
+}
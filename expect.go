@@ -0,0 +1,246 @@
+package gorunscript
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// expectWindowSize limita la salida que se conserva para buscar preguntas
+const expectWindowSize = 8 * 1024
+
+// ExpectOptions responde automáticamente a las preguntas del script, como expect. La
+// salida (stdout y stderr) se compara con las reglas desde la última respuesta enviada y
+// la primera regla que coincide escribe su respuesta en la entrada del script.
+//
+// Funciona con tuberías y en modo PTY. Con tuberías, bash solo muestra el texto de
+// `read -p` si la entrada es una terminal, así que esas preguntas requieren PTY; las
+// escritas con `echo -n` o printf funcionan en ambos modos.
+//
+// Por defecto solo se responde: un script que espera una entrada que ninguna regla
+// proporciona sigue esperando hasta que termine el contexto. PromptTimeout y
+// CloseInputOnIdle permiten detectarlo antes.
+type ExpectOptions struct {
+	Rules []ExpectRule
+
+	// PromptTimeout activa la detección de preguntas inesperadas: si el script escribe una
+	// línea sin terminar que ninguna regla responde y queda en silencio ese tiempo, se
+	// termina y el error es un *UnexpectedPromptError con su texto. Debe superar la
+	// duración de los pasos lentos que muestran progreso sin salto de línea, como
+	// `printf 'Compilando... '`. Cero o negativo la desactiva.
+	PromptTimeout time.Duration
+
+	// CloseInputOnIdle cierra la entrada del script cuando queda en silencio ese tiempo y
+	// no hay reglas obligatorias pendientes, de modo que un `read` sin respuesta recibe
+	// fin de archivo en lugar de esperar, p. ej. el de un `read -p` cuyo texto no se
+	// muestra sin terminal. Las reglas opcionales que no hayan coincidido antes ya no
+	// reciben respuesta. Solo con tuberías; cero o negativo lo desactiva.
+	CloseInputOnIdle time.Duration
+}
+
+// ExpectRule es una pregunta prevista y su respuesta
+type ExpectRule struct {
+	// Pattern es la expresión regular que reconoce la pregunta. Se compara con la salida
+	// sin códigos de color ni retornos de carro, p. ej. `Continue anyway\? \(y/N\) $`.
+	Pattern string
+
+	// Response es el texto que se envía; debe terminar en "\n" si el script lee una línea
+	Response string
+
+	// Timeout hace obligatoria la pregunta: si no aparece en ese tiempo desde el inicio o
+	// desde la última respuesta enviada, el script se termina con un *ExpectTimeoutError.
+	// Cero la hace opcional.
+	Timeout time.Duration
+}
+
+// ExpectMatch es una pregunta respondida automáticamente
+type ExpectMatch struct {
+	Pattern  string    // Pattern de la regla que respondió
+	Prompt   string    // Línea de la salida que coincidió
+	Response string    // Texto enviado
+	Time     time.Time // Momento de la respuesta
+}
+
+// expectRule es una ExpectRule con su patrón compilado
+type expectRule struct {
+	ExpectRule
+	re      *regexp.Regexp
+	matched bool
+}
+
+// expecter observa la salida del script y escribe las respuestas en su entrada
+type expecter struct {
+	rules         []*expectRule
+	promptTimeout time.Duration
+	closeOnIdle   time.Duration
+	input         io.Writer
+	closeInput    func()      // Cierra la entrada; nil si la cierra otro
+	fail          func(error) // Termina el script con la causa indicada
+
+	mu         sync.Mutex
+	window     []byte
+	lastOutput time.Time
+	lastAnswer time.Time
+	matches    []ExpectMatch
+	failed     bool
+	closed     bool // La entrada ya se cerró
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newExpecter compila las reglas de opts
+func newExpecter(opts *ExpectOptions) (*expecter, error) {
+	e := &expecter{
+		promptTimeout: opts.PromptTimeout,
+		closeOnIdle:   opts.CloseInputOnIdle,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+
+	for _, rule := range opts.Rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("error: patrón de Expect no válido %q: %w", rule.Pattern, err)
+		}
+		e.rules = append(e.rules, &expectRule{ExpectRule: rule, re: re})
+	}
+	return e, nil
+}
+
+// Write recibe la salida del script y responde si alguna regla coincide. La respuesta se
+// escribe sin retener e.mu, ya que puede bloquearse hasta que el script lea su entrada.
+func (e *expecter) Write(p []byte) (int, error) {
+	if response, ok := e.match(p); ok {
+		// Si el script ya no lee su entrada, el fallo se verá al terminar
+		_, _ = io.WriteString(e.input, response)
+	}
+	return len(p), nil
+}
+
+// match añade p a la salida observada y devuelve la respuesta de la primera regla que coincide
+func (e *expecter) match(p []byte) (string, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.window = append(e.window, p...)
+	if len(e.window) > expectWindowSize {
+		e.window = e.window[len(e.window)-expectWindowSize:]
+	}
+	e.lastOutput = time.Now()
+
+	if e.failed {
+		return "", false
+	}
+
+	text := expectText(e.window)
+	for _, rule := range e.rules {
+		loc := rule.re.FindStringIndex(text)
+		if loc == nil {
+			continue
+		}
+
+		prompt := text[:loc[1]]
+		if i := strings.LastIndexByte(strings.TrimRight(prompt, "\n"), '\n'); i >= 0 {
+			prompt = prompt[i+1:]
+		}
+		rule.matched = true
+		e.window = nil
+		e.lastAnswer = time.Now()
+		e.matches = append(e.matches, ExpectMatch{Pattern: rule.Pattern, Prompt: strings.TrimSpace(prompt), Response: rule.Response, Time: e.lastAnswer})
+		return rule.Response, true
+	}
+	return "", false
+}
+
+// start empieza a vigilar preguntas inesperadas y plazos; debe llamarse una vez iniciado el proceso
+func (e *expecter) start() {
+	e.mu.Lock()
+	e.lastOutput = time.Now()
+	e.lastAnswer = e.lastOutput
+	e.mu.Unlock()
+
+	go func() {
+		defer close(e.done)
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-e.stop:
+				return
+			case <-ticker.C:
+				if err := e.check(time.Now()); err != nil {
+					e.fail(err)
+					return
+				}
+			}
+		}
+	}()
+}
+
+// check devuelve el error de una pregunta inesperada o de una obligatoria que no llegó, y
+// cierra la entrada si se cumple CloseInputOnIdle
+func (e *expecter) check(now time.Time) error {
+	closeInput, err := e.checkLocked(now)
+	if closeInput {
+		e.closeInput()
+	}
+	return err
+}
+
+// checkLocked hace las comprobaciones de check con e.mu retenido e indica si hay que
+// cerrar la entrada
+func (e *expecter) checkLocked(now time.Time) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	text := expectText(e.window)
+	idle := now.Sub(e.lastOutput)
+	if e.promptTimeout > 0 && idle >= e.promptTimeout {
+		pending := text[strings.LastIndexByte(text, '\n')+1:]
+		if strings.TrimSpace(pending) != "" {
+			e.failed = true
+			return false, &UnexpectedPromptError{Prompt: pending, Idle: e.promptTimeout}
+		}
+	}
+
+	required := false
+	for _, rule := range e.rules {
+		if rule.matched || rule.Timeout <= 0 {
+			continue
+		}
+		if now.Sub(e.lastAnswer) >= rule.Timeout {
+			e.failed = true
+			return false, &ExpectTimeoutError{Pattern: rule.Pattern, Timeout: rule.Timeout, Output: text}
+		}
+		required = true
+	}
+
+	if e.closeOnIdle > 0 && idle >= e.closeOnIdle && !required && e.closeInput != nil && !e.closed {
+		e.closed = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// finish deja de vigilar, cierra la entrada y devuelve las preguntas respondidas
+func (e *expecter) finish() []ExpectMatch {
+	close(e.stop)
+	<-e.done
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closeInput != nil && !e.closed {
+		e.closed = true
+		e.closeInput()
+	}
+	return e.matches
+}
+
+// expectText prepara la salida para compararla: sin códigos de color ni retornos de carro
+func expectText(output []byte) string {
+	text := ansiColor.ReplaceAllString(string(output), "")
+	return strings.ReplaceAll(text, "\r", "")
+}
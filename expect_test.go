@@ -0,0 +1,146 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpectText(t *testing.T) {
+	got := expectText([]byte("\x1b[0;33mContinue anyway? (y/N) \x1b[0m\r\nok\r\n"))
+	if got != "Continue anyway? (y/N) \nok\n" {
+		t.Errorf("Texto inesperado: %q", got)
+	}
+}
+
+func TestExpectPipeMode(t *testing.T) {
+	runner := NewBashRunnerWithOptions(newTempProject(t, map[string]string{
+		"preguntas.sh": "#!/bin/bash\n" +
+			"printf 'Nombre: '\nread name\n" +
+			"printf 'Continue anyway? (y/N) ' >&2\nread -n 1 answer\necho\n" +
+			"echo \"hola $name, respuesta $answer\"\n",
+		"inesperada.sh": "#!/bin/bash\nprintf 'Nombre: '\nread name\nprintf 'Contraseña: '\nread pass\necho fin\n",
+		"lento.sh":      "#!/bin/bash\necho trabajando\nsleep 5\n",
+		"tardia.sh":     "#!/bin/bash\necho start\nsleep 1\nprintf 'Nombre: '\nread name\necho \"got=$name\"\n",
+		"progreso.sh":   "#!/bin/bash\nprintf 'Compilando... '\nsleep 1\necho hecho\n",
+		"read-p.sh": "#!/bin/bash\necho preparando\n" +
+			"read -p 'Continue anyway? (y/N) ' answer || { echo 'Operation cancelled'; exit 1; }\necho \"respuesta $answer\"\n",
+	}))
+	runner.SetWorkspaceRoot(t.TempDir())
+	ctx := context.Background()
+
+	t.Run("Respuestas", func(t *testing.T) {
+		opts := ExecOptions{Expect: &ExpectOptions{Rules: []ExpectRule{
+			{Pattern: `Continue anyway\? \(y/N\) $`, Response: "y", Timeout: 2 * time.Second},
+			{Pattern: `Nombre: $`, Response: "Ana María\n"},
+		}}}
+		res, err := runner.Run(ctx, opts, "preguntas")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+		if !strings.Contains(res.Stdout, "hola Ana María, respuesta y\n") {
+			t.Errorf("Salida inesperada: %q", res.Stdout)
+		}
+		if len(res.Expects) != 2 || res.Expects[0].Prompt != "Nombre:" || res.Expects[1].Prompt != "Continue anyway? (y/N)" {
+			t.Errorf("Respuestas inesperadas: %+v", res.Expects)
+		}
+	})
+
+	t.Run("Pregunta inesperada", func(t *testing.T) {
+		opts := ExecOptions{Expect: &ExpectOptions{
+			Rules:         []ExpectRule{{Pattern: `Nombre: $`, Response: "Ana\n"}},
+			PromptTimeout: 200 * time.Millisecond,
+		}}
+
+		start := time.Now()
+		res, err := runner.Run(ctx, opts, "inesperada")
+		var promptErr *UnexpectedPromptError
+		if !errors.Is(err, ErrUnexpectedPrompt) || !errors.As(err, &promptErr) {
+			t.Fatalf("Se esperaba ErrUnexpectedPrompt, se obtuvo %v", err)
+		}
+		if promptErr.Prompt != "Contraseña: " || !strings.Contains(err.Error(), `"Contraseña: "`) {
+			t.Errorf("Pregunta inesperada mal informada: %v", err)
+		}
+		if res.Success() || time.Since(start) > 3*time.Second {
+			t.Errorf("El script debía terminarse enseguida: %+v", res)
+		}
+	})
+
+	t.Run("Pregunta opcional tras un silencio", func(t *testing.T) {
+		// El silencio previo a una pregunta con regla no debe cerrar la entrada
+		opts := ExecOptions{Expect: &ExpectOptions{
+			Rules:         []ExpectRule{{Pattern: `Nombre: $`, Response: "Ana\n"}},
+			PromptTimeout: 300 * time.Millisecond,
+		}}
+		res, err := runner.Run(ctx, opts, "tardia")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+		if !strings.Contains(res.Stdout, "got=Ana\n") || len(res.Expects) != 1 {
+			t.Errorf("La pregunta no recibió su respuesta: %q, %+v", res.Stdout, res.Expects)
+		}
+	})
+
+	t.Run("Paso lento sin salto de línea", func(t *testing.T) {
+		// Sin PromptTimeout una línea a medio escribir no se considera una pregunta
+		opts := ExecOptions{Expect: &ExpectOptions{Rules: []ExpectRule{{Pattern: `Nombre: $`, Response: "Ana\n"}}}}
+		res, err := runner.Run(ctx, opts, "progreso")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+		if res.Stdout != "Compilando... hecho\n" {
+			t.Errorf("Salida inesperada: %q", res.Stdout)
+		}
+	})
+
+	t.Run("read -p sin terminal", func(t *testing.T) {
+		// Sin terminal bash no muestra el texto de read -p: la regla no puede coincidir y
+		// el script no debe quedarse esperando hasta el plazo del contexto
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		opts := ExecOptions{Expect: &ExpectOptions{
+			Rules:            []ExpectRule{{Pattern: `Continue anyway\? \(y/N\) $`, Response: "y\n"}},
+			CloseInputOnIdle: 300 * time.Millisecond,
+		}}
+
+		start := time.Now()
+		res, err := runner.Run(ctx, opts, "read-p")
+		if errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 5*time.Second {
+			t.Fatalf("El script se quedó esperando la entrada: %v", err)
+		}
+		if res.ExitCode != 1 || !strings.Contains(res.Stdout, "Operation cancelled") {
+			t.Errorf("Se esperaba que el script recibiera fin de archivo: %d %q", res.ExitCode, res.Stdout)
+		}
+	})
+
+	t.Run("Pregunta obligatoria que no llega", func(t *testing.T) {
+		opts := ExecOptions{Expect: &ExpectOptions{
+			Rules: []ExpectRule{{Pattern: `Nombre: $`, Response: "Ana\n", Timeout: 300 * time.Millisecond}},
+		}}
+		res, err := runner.Run(ctx, opts, "lento")
+		var timeoutErr *ExpectTimeoutError
+		if !errors.Is(err, ErrExpectTimeout) || !errors.As(err, &timeoutErr) {
+			t.Fatalf("Se esperaba ErrExpectTimeout, se obtuvo %v", err)
+		}
+		if timeoutErr.Pattern != `Nombre: $` || !strings.Contains(timeoutErr.Output, "trabajando") {
+			t.Errorf("Error inesperado: %+v", timeoutErr)
+		}
+		if res.Success() {
+			t.Error("El script debía terminarse")
+		}
+	})
+
+	t.Run("Opciones no válidas", func(t *testing.T) {
+		opts := ExecOptions{Expect: &ExpectOptions{Rules: []ExpectRule{{Pattern: "("}}}}
+		if _, err := runner.Run(ctx, opts, "preguntas"); err == nil {
+			t.Error("Se esperaba error por un patrón no válido")
+		}
+
+		opts = ExecOptions{Stdin: strings.NewReader("x\n"), Expect: &ExpectOptions{}}
+		if _, err := runner.Run(ctx, opts, "preguntas"); err == nil {
+			t.Error("Se esperaba error al combinar Stdin y Expect")
+		}
+	})
+}
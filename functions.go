@@ -0,0 +1,171 @@
+package gorunscript
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cdvelop/gorunscript/internal/shellquote"
+)
+
+// FunctionInfo describe una función de shell definida en un script del catálogo
+type FunctionInfo struct {
+	Script string // Script que la define, con extensión
+	Name   string
+	Line   int    // Línea de la definición, empezando en 1
+	Doc    string // Comentario inmediatamente anterior, sin los #
+}
+
+// parseFunctions devuelve las funciones que f define, en orden de aparición
+func parseFunctions(f scriptFile) []FunctionInfo {
+	if !isShellScript(f) {
+		return nil
+	}
+
+	var functions []FunctionInfo
+	var comment []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(f.content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(f.content)+1)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "#!") {
+			comment = append(comment, strings.TrimSpace(strings.TrimLeft(line, "#")))
+			continue
+		}
+		if m := functionDefinition.FindStringSubmatch(line); m != nil {
+			functions = append(functions, FunctionInfo{Script: f.name, Name: m[1] + m[2], Line: n, Doc: strings.Join(comment, "\n")})
+		}
+		comment = nil
+	}
+	return functions
+}
+
+// Functions devuelve las funciones que define el script indicado, en orden de aparición.
+// El nombre admite omitir la extensión, como en Run. No incluye las de los archivos que
+// el script carga con source, aunque ExecuteFunction también puede invocarlas.
+func (sr *ScriptRunner) Functions(scriptName string) ([]FunctionInfo, error) {
+	files, _, err := sr.resolveScripts()
+	if err != nil {
+		return nil, err
+	}
+
+	script, ok := findScript(files, scriptName, sr.interpreters)
+	if !ok {
+		return nil, newScriptNotFoundError(script.name, files)
+	}
+	return parseFunctions(script), nil
+}
+
+// AllFunctions devuelve las funciones definidas en todos los scripts de shell del
+// catálogo combinado, ordenadas por script y línea
+func (sr *ScriptRunner) AllFunctions() ([]FunctionInfo, error) {
+	files, _, err := sr.resolveScripts()
+	if err != nil {
+		return nil, err
+	}
+
+	var functions []FunctionInfo
+	for _, f := range files {
+		functions = append(functions, parseFunctions(f)...)
+	}
+	return functions, nil
+}
+
+// ExecuteFunction carga con source el script indicado, normalmente una biblioteca como
+// git-utils.sh, e invoca la función con args como parámetros posicionales, sin que el
+// shell los reinterprete. El código de nivel superior del script se ejecuta al cargarlo.
+// La función puede estar definida en el script o en los archivos que este carga con
+// source; si no existe devuelve un *FunctionNotFoundError sin ejecutar nada.
+func (sr *ScriptRunner) ExecuteFunction(ctx context.Context, scriptName, function string, args ...string) (*Result, error) {
+	return sr.RunFunction(ctx, ExecOptions{}, scriptName, function, args...)
+}
+
+// RunFunction es como ExecuteFunction pero aplicando las opciones de la llamada
+func (sr *ScriptRunner) RunFunction(ctx context.Context, opts ExecOptions, scriptName, function string, args ...string) (*Result, error) {
+	res := sr.newResult(scriptName, args)
+	res.Function = function
+
+	inv, err := sr.prepare(ctx, opts)
+	if err != nil {
+		return res, err
+	}
+	defer inv.release()
+
+	script, ok := findScript(inv.files, scriptName, sr.interpreters)
+	if !ok {
+		return res, newScriptNotFoundError(script.name, inv.files)
+	}
+	res.Script = script.name
+	res.Layer = script.layer
+
+	if !isShellScript(script) {
+		return res, fmt.Errorf("error: %s no es un script de shell, no se pueden invocar sus funciones", script.name)
+	}
+
+	available := sr.loadedFunctions(inv.files, script)
+	found := false
+	for _, name := range available {
+		if name == function {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return res, &FunctionNotFoundError{Script: script.name, Function: function, Available: available}
+	}
+
+	// El script se resuelve por el PATH, donde extract deja la caché, y la función se
+	// invoca con "$@" para pasar los argumentos tal cual
+	source := fmt.Sprintf("#!/bin/bash\nsource %s || exit $?\n%s \"$@\"\n", shellquote.Quote(script.name), shellquote.Quote(function))
+	wrapper := scriptFile{name: inlineScriptName, content: []byte(source)}
+	if err := sr.extract(ctx, inv, wrapper, script); err != nil {
+		return res, err
+	}
+
+	res.ScriptPath, err = writeInlineScript(inv, source)
+	if err != nil {
+		return res, err
+	}
+
+	return res, sr.execute(ctx, inv, opts, sr.interpreterFor(wrapper), res)
+}
+
+// loadedFunctions devuelve, ordenados, los nombres de las funciones que quedan definidas
+// al cargar script: las suyas y las de los archivos que carga con source, transitivamente
+func (sr *ScriptRunner) loadedFunctions(catalog []scriptFile, script scriptFile) []string {
+	byName := make(map[string]scriptFile, len(catalog))
+	for _, f := range catalog {
+		byName[f.name] = f
+	}
+
+	names := make(map[string]bool)
+	visited := make(map[string]bool)
+	var visit func(f scriptFile)
+	visit = func(f scriptFile) {
+		if visited[f.name] {
+			return
+		}
+		visited[f.name] = true
+
+		for _, fn := range parseFunctions(f) {
+			names[fn.Name] = true
+		}
+		for _, ref := range parseScriptRefs(f.content, sr.interpreters) {
+			if lib, ok := byName[ref.name]; ok && ref.kind == refSource {
+				visit(lib)
+			}
+		}
+	}
+	visit(script)
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
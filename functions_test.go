@@ -0,0 +1,143 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFunctions(t *testing.T) {
+	f := scriptFile{name: "lib.sh", content: []byte("#!/bin/bash\n" +
+		"# Saluda\n# a alguien\nsaludar() {\n  echo hola\n}\n\n" +
+		"function despedir {\n  echo adiós\n}\n" +
+		"# suelto\n\nfunction con_parentesis() {\n:\n}\n")}
+
+	want := []FunctionInfo{
+		{Script: "lib.sh", Name: "saludar", Line: 4, Doc: "Saluda\na alguien"},
+		{Script: "lib.sh", Name: "despedir", Line: 8},
+		{Script: "lib.sh", Name: "con_parentesis", Line: 13},
+	}
+	if got := parseFunctions(f); !reflect.DeepEqual(got, want) {
+		t.Errorf("Funciones inesperadas:\n got: %+v\nwant: %+v", got, want)
+	}
+
+	if got := parseFunctions(scriptFile{name: "x.py", content: []byte("def f():\n  pass\n")}); len(got) != 0 {
+		t.Errorf("No se esperaban funciones en un script de Python: %+v", got)
+	}
+}
+
+func TestFunctionsDiscovery(t *testing.T) {
+	runner := NewBashRunner()
+
+	functions, err := runner.Functions("git-utils")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	var names []string
+	for _, fn := range functions {
+		names = append(names, fn.Name)
+	}
+	want := []string{"create_readme", "create_changes_file", "init_base_files", "init_new_repo",
+		"setup_git_remote", "push_to_remote", "create_git_tag", "create_initial_commit"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Funciones inesperadas: %q", names)
+	}
+	if functions[0].Doc != "Create README.md file" {
+		t.Errorf("Documentación inesperada: %q", functions[0].Doc)
+	}
+
+	if _, err := runner.Functions("no-existe"); !errors.Is(err, ErrScriptNotFound) {
+		t.Errorf("Se esperaba ErrScriptNotFound, se obtuvo %v", err)
+	}
+
+	all, err := runner.AllFunctions()
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	scripts := make(map[string]bool)
+	for _, fn := range all {
+		scripts[fn.Script] = true
+	}
+	if !scripts["git-utils.sh"] || !scripts["functions.sh"] {
+		t.Errorf("Se esperaban funciones de git-utils.sh y functions.sh: %v", scripts)
+	}
+}
+
+func TestExecuteFunction(t *testing.T) {
+	projectRoot := newTempProject(t, map[string]string{
+		"lib.sh": "#!/bin/bash\nsource functions.sh\necho cargando\n" +
+			"mostrar() {\n  printf '[%s]' \"$@\"\n}\n" +
+			"fallar() {\n  return 3\n}\n",
+	})
+	runner := NewBashRunnerWithOptions(projectRoot)
+	runner.SetWorkspaceRoot(t.TempDir())
+	ctx := context.Background()
+
+	t.Run("Argumentos sin reinterpretar", func(t *testing.T) {
+		res, err := runner.ExecuteFunction(ctx, "lib", "mostrar", "con espacios", "$HOME", "'comillas'", "")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+		if res.Stdout != "cargando\n[con espacios][$HOME]['comillas'][]" {
+			t.Errorf("Salida inesperada: %q", res.Stdout)
+		}
+		if res.Script != "lib.sh" || res.Function != "mostrar" || res.Layer != LayerProject {
+			t.Errorf("Resultado inesperado: %+v", res)
+		}
+	})
+
+	t.Run("Función de un archivo cargado con source", func(t *testing.T) {
+		res, err := runner.ExecuteFunction(ctx, "lib", "success", "listo")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+		if !reflect.DeepEqual(res.Report.Successes(), []string{"listo"}) {
+			t.Errorf("Informe inesperado: %q", res.Output)
+		}
+	})
+
+	t.Run("Código de salida de la función", func(t *testing.T) {
+		res, err := runner.ExecuteFunction(ctx, "lib", "fallar")
+		var scriptErr *ScriptError
+		if !errors.As(err, &scriptErr) || res.ExitCode != 3 {
+			t.Errorf("Se esperaba un ScriptError con código 3: %v, %d", err, res.ExitCode)
+		}
+	})
+
+	t.Run("Función inexistente", func(t *testing.T) {
+		res, err := runner.ExecuteFunction(ctx, "lib", "no_existe")
+		var fnErr *FunctionNotFoundError
+		if !errors.Is(err, ErrFunctionNotFound) || !errors.As(err, &fnErr) {
+			t.Fatalf("Se esperaba ErrFunctionNotFound, se obtuvo %v", err)
+		}
+		if !strings.Contains(err.Error(), "mostrar") || res.Output != "" {
+			t.Errorf("Error o resultado inesperado: %v, %q", err, res.Output)
+		}
+	})
+
+	t.Run("Script inexistente", func(t *testing.T) {
+		if _, err := runner.ExecuteFunction(ctx, "no-existe", "mostrar"); !errors.Is(err, ErrScriptNotFound) {
+			t.Errorf("Se esperaba ErrScriptNotFound, se obtuvo %v", err)
+		}
+	})
+}
+
+func TestExecuteFunctionGitUtils(t *testing.T) {
+	runner := NewBashRunner()
+	runner.SetWorkspaceRoot(t.TempDir())
+	dir := t.TempDir()
+
+	res, err := runner.RunFunction(context.Background(), ExecOptions{Dir: dir}, "git-utils", "create_readme", "mi proyecto")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil || string(content) != "# mi proyecto\n" {
+		t.Errorf("README.md inesperado: %q, %v", content, err)
+	}
+}
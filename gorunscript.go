@@ -1,6 +1,7 @@
 package gorunscript
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	"io/fs"
@@ -9,6 +10,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 )
 
 //go:embed bash_scripts/*.sh
@@ -16,356 +18,197 @@ var bash_scripts embed.FS
 
 // ScriptRunner es un manejador para ejecutar scripts de diferentes tipos
 type ScriptRunner struct {
-	fsys           embed.FS
-	baseDir        string
-	interpreterCmd string
-	cleanScripts   bool   // Indica si se deben limpiar los scripts después de ejecutarlos
-	projectRoot    string // Ruta raíz del proyecto para configuración explícita
+	layers         []scriptLayer        // Orígenes de los scripts, de menor a mayor precedencia
+	interpreterCmd string               // Intérprete principal del runner, usado para .sh y como respaldo
+	interpreters   *InterpreterRegistry // Intérpretes por extensión y shebang
+	cleanScripts   bool                 // Indica si se deben limpiar los scripts después de ejecutarlos
+	killGrace      time.Duration        // Tiempo de espera entre SIGTERM y SIGKILL al cancelar
+	workspaceRoot  string               // Raíz de los espacios de trabajo, vacío para ~/.gorunscript
+	pathDirs       []string             // Directorios antepuestos al PATH de cada ejecución
+	cacheMaxAge    time.Duration        // Antigüedad sin uso a partir de la cual se borra una entrada de la caché
+
+	validateBeforeRun bool // Validar la sintaxis antes de cada ejecución
 }
 
+// defaultKillGrace es el tiempo que se espera tras SIGTERM antes de forzar SIGKILL
+const defaultKillGrace = 5 * time.Second
+
 // NewBashRunner crea un manejador para scripts bash
 func NewBashRunner() *ScriptRunner {
-	// Comando por defecto
-	interpreterCmd := "bash"
-
-	if runtime.GOOS == "windows" {
-		// Usar Git Bash directamente en Windows
-		interpreterCmd = `C:\Program Files\Git\bin\bash.exe`
-	}
+	// Comando por defecto, Git Bash en Windows
+	interpreterCmd := defaultBashCmd()
 
 	return &ScriptRunner{
-		fsys:           bash_scripts,
-		baseDir:        "bash_scripts",
+		layers:         []scriptLayer{{name: LayerEmbedded, fsys: bash_scripts, baseDir: "bash_scripts"}},
 		interpreterCmd: interpreterCmd,
+		interpreters:   newDefaultInterpreters(interpreterCmd),
 		cleanScripts:   true, // Por defecto limpia los scripts
-		projectRoot:    "",   // Por defecto no usa ruta específica
+		killGrace:      defaultKillGrace,
+		cacheMaxAge:    defaultCacheMaxAge,
 	}
 }
 
-// NewBashRunnerWithOptions crea un manejador para scripts bash con opciones avanzadas
+// NewBashRunnerWithOptions crea un manejador para scripts bash que añade el directorio
+// bash_scripts de projectRoot como capa "project" sobre los scripts embebidos
 func NewBashRunnerWithOptions(projectRoot string) *ScriptRunner {
 	runner := NewBashRunner()
-	runner.projectRoot = projectRoot
+	runner.AddLayer(LayerProject, os.DirFS(filepath.Join(projectRoot, "bash_scripts")), ".")
 	return runner
 }
 
-// NewScriptRunner crea un manejador para scripts personalizados
-func NewScriptRunner(fsys embed.FS, baseDir, interpreterCmd string) *ScriptRunner {
+// NewScriptRunner crea un manejador para scripts personalizados. fsys puede ser cualquier
+// fs.FS: un embed.FS, os.DirFS, un fstest.MapFS en tests, un zip.Reader, etc.
+// interpreterCmd ejecuta los scripts .sh y .bash; el resto sigue el registro por defecto.
+func NewScriptRunner(fsys fs.FS, baseDir, interpreterCmd string) *ScriptRunner {
 	return &ScriptRunner{
-		fsys:           fsys,
-		baseDir:        baseDir,
+		layers:         []scriptLayer{{name: LayerBase, fsys: fsys, baseDir: baseDir}},
 		interpreterCmd: interpreterCmd,
+		interpreters:   newDefaultInterpreters(interpreterCmd),
 		cleanScripts:   true,
-		projectRoot:    "",
+		killGrace:      defaultKillGrace,
+		cacheMaxAge:    defaultCacheMaxAge,
 	}
 }
 
+// Interpreters devuelve el registro de intérpretes del runner para añadir o cambiar
+// asociaciones por extensión o shebang
+func (sr *ScriptRunner) Interpreters() *InterpreterRegistry {
+	return sr.interpreters
+}
+
 // SetKeepScripts configura si se deben mantener los scripts extraídos después de la ejecución
 func (sr *ScriptRunner) SetKeepScripts(keep bool) {
 	sr.cleanScripts = !keep
 }
 
-// getScriptsDir obtiene el directorio donde se extraerán los scripts
-func getScriptsDir() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("error obteniendo directorio de usuario: %w", err)
-	}
-
-	scriptDir := filepath.Join(homeDir, ".gorunscript")
-
-	// Crear el directorio si no existe
-	if err := os.MkdirAll(scriptDir, 0755); err != nil {
-		return "", fmt.Errorf("error creando directorio para scripts: %w", err)
+// SetKillGracePeriod configura cuánto se espera tras enviar SIGTERM al grupo de procesos
+// antes de forzar SIGKILL cuando se cancela una ejecución
+func (sr *ScriptRunner) SetKillGracePeriod(d time.Duration) {
+	if d < 0 {
+		d = 0
 	}
-
-	return scriptDir, nil
+	sr.killGrace = d
 }
 
-// ExecuteScript ejecuta un script y devuelve el código de salida y la salida del comando
-func (sr *ScriptRunner) ExecuteScript(scriptName string, args ...string) (int, string, error) {
-	// Asegurarse de que el nombre del script tiene extensión correcta
-	if !strings.Contains(scriptName, ".") {
-		scriptName = scriptName + ".sh"
-	}
-
-	// Obtener directorio para los scripts
-	scriptsDir, err := getScriptsDir()
-	if err != nil {
-		return 1, "", err
-	}
-
-	// Limpiar el directorio de scripts antes de copiar/extraer nuevos scripts
-	if err := os.RemoveAll(scriptsDir); err != nil {
-		return 1, "", fmt.Errorf("error limpiando directorio de scripts: %w", err)
-	}
-
-	if err := os.MkdirAll(scriptsDir, 0755); err != nil {
-		return 1, "", fmt.Errorf("error recreando directorio de scripts: %w", err)
-	}
-
-	// Si se ha proporcionado una ruta específica al proyecto, usamos esa para tests
-	if sr.projectRoot != "" {
-		// En tests, queremos usar los scripts reales del proyecto, no los embebidos
-		srcDir := filepath.Join(sr.projectRoot, "bash_scripts")
-
-		// Verificar que el directorio existe
-		if _, err := os.Stat(srcDir); os.IsNotExist(err) {
-			return 1, "", fmt.Errorf("error: directorio de scripts no encontrado en %s", srcDir)
-		}
-
-		// Listar y mostrar el contenido del directorio para debugging
-		files, _ := os.ReadDir(srcDir)
-		fileNames := make([]string, 0, len(files))
-		for _, file := range files {
-			fileNames = append(fileNames, file.Name())
-		}
-		fmt.Printf("Archivos en %s: %v\n", srcDir, fileNames)
-
-		// Copiar los scripts directamente al directorio de scripts (sin subdirectorios)
-		if err := copyDirContentsFlat(srcDir, scriptsDir); err != nil {
-			return 1, "", fmt.Errorf("error copiando scripts: %w", err)
-		}
-	} else {
-		// Extraer todos los scripts al directorio permanente desde el FS embebido
-		if err := extractScriptsFlat(sr.fsys, sr.baseDir, scriptsDir); err != nil {
-			return 1, "", fmt.Errorf("error extrayendo scripts: %w", err)
-		}
-	}
-
-	// Limpiar los scripts al terminar si así se ha configurado
-	if sr.cleanScripts {
-		defer func() {
-			_ = os.RemoveAll(scriptsDir)
-			// Crear el directorio de nuevo para que esté listo para el próximo uso
-			_ = os.MkdirAll(scriptsDir, 0755)
-		}()
-	}
-
-	// Ruta al script principal en el directorio de scripts
-	scriptPath := filepath.Join(scriptsDir, scriptName)
-
-	// Verificar si el script existe
-	if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
-		// Listar archivos en el directorio para debugging
-		files, _ := os.ReadDir(scriptsDir)
-		fileNames := make([]string, 0, len(files))
-		for _, file := range files {
-			fileNames = append(fileNames, file.Name())
-		}
-		return 1, "", fmt.Errorf("error: el script '%s' no existe. Archivos disponibles: %v", scriptName, fileNames)
-	}
+// SetWorkspaceRoot configura el directorio bajo el cual se crean los espacios de trabajo
+// aislados de cada ejecución. Por defecto se usa ~/.gorunscript
+func (sr *ScriptRunner) SetWorkspaceRoot(dir string) {
+	sr.workspaceRoot = dir
+}
 
-	// Asegurarse de que todos los scripts son ejecutables
-	if err := makeScriptsExecutable(scriptsDir); err != nil {
-		return 1, "", fmt.Errorf("error haciendo los scripts ejecutables: %w", err)
-	}
+// SetCacheMaxAge configura tras cuánto tiempo sin usarse se elimina una versión de los
+// scripts extraídos en la caché. Por defecto 7 días; cero o negativo desactiva la limpieza.
+func (sr *ScriptRunner) SetCacheMaxAge(d time.Duration) {
+	sr.cacheMaxAge = d
+}
 
-	var cmd *exec.Cmd
+// PrependPath antepone dir al PATH de todas las ejecuciones del runner, después del
+// directorio de scripts. Permite sustituir comandos externos, p. ej. con los comandos
+// falsos de gorunscripttest. Varias llamadas acumulan directorios por orden de prioridad.
+func (sr *ScriptRunner) PrependPath(dir string) {
+	sr.pathDirs = append(sr.pathDirs, dir)
+}
 
-	if runtime.GOOS == "windows" {
-		// Ejecutar script con Git Bash en Windows convirtiendo rutas a formato Unix
-		unixPath := strings.ReplaceAll(scriptPath, "\\", "/")
-		// Combine script path and arguments into a single quoted command string
-		// Properly escape arguments and use bash positional parameters
-		escapedArgs := make([]string, len(args))
-		for i, arg := range args {
-			escapedArgs[i] = fmt.Sprintf("%q", arg)
-		}
-		fullCommand := fmt.Sprintf("%q \"$@\"", unixPath)
-		cmdArgs := []string{"-c", fullCommand, "--"}
-		cmdArgs = append(cmdArgs, args...)
-		cmd = exec.Command(sr.interpreterCmd, cmdArgs...)
-	} else {
-		// En otros sistemas ejecutar directamente
-		cmd = exec.Command(sr.interpreterCmd, append([]string{scriptPath}, args...)...)
+// getWorkspaceRoot obtiene el directorio raíz de los espacios de trabajo
+func (sr *ScriptRunner) getWorkspaceRoot() (string, error) {
+	if sr.workspaceRoot != "" {
+		return sr.workspaceRoot, nil
 	}
 
-	// Establecer el directorio de trabajo al directorio donde están los scripts
-	cmd.Dir = scriptsDir
-
-	// Configurar variables de entorno para asegurar la estabilidad
-	env := os.Environ()
-	cmd.Env = append(env, "LANG=C")
-
-	// Ejecutar y capturar la salida
-	output, err := cmd.CombinedOutput()
-	outputStr := string(output)
-
-	// Determinar el código de salida y manejar errores
+	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		var exitCode int
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
-		} else {
-			exitCode = 1
-		}
-		return exitCode, outputStr, fmt.Errorf("error ejecutando script: %w", err)
+		return "", fmt.Errorf("error obteniendo directorio de usuario: %w", err)
 	}
 
-	return 0, outputStr, nil
+	return filepath.Join(homeDir, ".gorunscript"), nil
 }
 
-// copyDirContentsFlat copia el contenido de un directorio a otro, sin mantener la estructura de subdirectorios
-func copyDirContentsFlat(srcDir, destDir string) error {
-	// Asegurarnos de que el directorio destino existe
-	if err := os.MkdirAll(destDir, 0755); err != nil {
+// CleanWorkspaces elimina todos los espacios de trabajo que no están en uso, incluidos los
+// conservados con SetKeepScripts(true), y las versiones de la caché sin uso reciente
+func (sr *ScriptRunner) CleanWorkspaces() error {
+	root, err := sr.getWorkspaceRoot()
+	if err != nil {
 		return err
 	}
-
-	// Recorrer el directorio origen pero solo copiar los archivos del nivel superior
-	files, err := os.ReadDir(srcDir)
-	if err != nil {
-		return fmt.Errorf("error al leer directorio %s: %w", srcDir, err)
+	if err := collectWorkspaces(root, true); err != nil {
+		return err
 	}
+	return pruneScriptCache(filepath.Join(root, cacheDirName), sr.cacheMaxAge)
+}
 
-	for _, file := range files {
-		if file.IsDir() {
-			continue // Ignorar subdirectorios
-		}
-
-		srcPath := filepath.Join(srcDir, file.Name())
-		destPath := filepath.Join(destDir, file.Name())
+// ExecuteScript ejecuta un script y devuelve el código de salida y la salida del comando
+func (sr *ScriptRunner) ExecuteScript(scriptName string, args ...string) (int, string, error) {
+	return sr.ExecuteScriptContext(context.Background(), scriptName, args...)
+}
 
-		// Copiar el archivo
-		data, err := os.ReadFile(srcPath)
-		if err != nil {
-			return fmt.Errorf("error al leer archivo %s: %w", srcPath, err)
-		}
+// ExecuteScriptContext ejecuta un script respetando la cancelación y el plazo del contexto.
+// El intérprete se inicia en su propio grupo de procesos; al cancelar se envía SIGTERM a
+// todo el grupo y, pasado el periodo de gracia, SIGKILL. Si la ejecución fue interrumpida
+// por el contexto el error envuelve ctx.Err(), de modo que errors.Is(err, context.DeadlineExceeded)
+// distingue un timeout de una salida con código distinto de cero.
+func (sr *ScriptRunner) ExecuteScriptContext(ctx context.Context, scriptName string, args ...string) (int, string, error) {
+	res, err := sr.Run(ctx, ExecOptions{}, scriptName, args...)
+	return res.ExitCode, res.Output, err
+}
 
-		if err := os.WriteFile(destPath, data, 0644); err != nil {
-			return fmt.Errorf("error al escribir archivo %s: %w", destPath, err)
-		}
-	}
+// ExecuteScriptWithOptions ejecuta un script aplicando las opciones de la llamada:
+// directorio de trabajo, entorno, stdin y transmisión de la salida
+func (sr *ScriptRunner) ExecuteScriptWithOptions(ctx context.Context, opts ExecOptions, scriptName string, args ...string) (int, string, error) {
+	res, err := sr.Run(ctx, opts, scriptName, args...)
+	return res.ExitCode, res.Output, err
+}
 
-	return nil
+// ExecuteScriptStream ejecuta un script transmitiendo stdout y stderr por separado mientras
+// se ejecuta, hacia los io.Writer y/o el callback por línea de streams. Al terminar devuelve
+// el código de salida y el texto capturado de cada flujo.
+func (sr *ScriptRunner) ExecuteScriptStream(ctx context.Context, streams StreamOptions, scriptName string, args ...string) (int, string, string, error) {
+	res, err := sr.Run(ctx, ExecOptions{Streams: streams}, scriptName, args...)
+	return res.ExitCode, res.Stdout, res.Stderr, err
 }
 
-// extractScriptsFlat extrae todos los scripts del filesystem embebido al directorio de destino, sin mantener la estructura de subdirectorios
-func extractScriptsFlat(fsys embed.FS, baseDir string, targetDir string) error {
-	// Leer todos los archivos en el directorio base
-	entries, err := fs.ReadDir(fsys, baseDir)
+// Run ejecuta un script con las opciones indicadas y devuelve el resultado detallado.
+// El Result nunca es nil: si la ejecución no llegó a iniciarse, ExitCode vale 1.
+func (sr *ScriptRunner) Run(ctx context.Context, opts ExecOptions, scriptName string, args ...string) (*Result, error) {
+	res := sr.newResult(scriptName, args)
+
+	inv, err := sr.prepare(ctx, opts)
 	if err != nil {
-		return fmt.Errorf("error al leer directorio embebido %s: %w", baseDir, err)
+		return res, err
 	}
+	defer inv.release()
 
-	// Extraer solo los archivos .sh del nivel superior
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue // Ignorar subdirectorios
-		}
-
-		name := entry.Name()
-		if !strings.HasSuffix(name, ".sh") {
-			continue // Ignorar archivos que no sean .sh
-		}
-
-		// Leer el contenido del archivo
-		content, err := fsys.ReadFile(filepath.Join(baseDir, name))
-		if err != nil {
-			return fmt.Errorf("error al leer archivo embebido %s: %w", name, err)
-		}
-
-		// Escribir el archivo en el directorio destino
-		destPath := filepath.Join(targetDir, name)
-		if err := os.WriteFile(destPath, content, 0644); err != nil {
-			return fmt.Errorf("error al escribir archivo %s: %w", destPath, err)
-		}
+	// Resolver el nombre, admitiendo nombres sin extensión, y su intérprete
+	script, ok := findScript(inv.files, scriptName, sr.interpreters)
+	if !ok {
+		return res, newScriptNotFoundError(script.name, inv.files)
 	}
 
-	return nil
-}
-
-// makeScriptsExecutable hace todos los scripts en el directorio ejecutables
-func makeScriptsExecutable(dirPath string) error {
-	// En Windows no es necesario hacer los scripts ejecutables
-	if runtime.GOOS == "windows" {
-		return nil
+	if err := sr.extract(ctx, inv, script); err != nil {
+		return res, err
 	}
 
-	return filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if !info.IsDir() && strings.HasSuffix(path, ".sh") {
-			if err := os.Chmod(path, 0755); err != nil {
-				return fmt.Errorf("error al hacer ejecutable el script %s: %w", path, err)
-			}
-		}
-		return nil
-	})
+	res.Script = script.name
+	res.ScriptPath = filepath.Join(inv.scriptsDir, script.name)
+	res.Layer = script.layer
+
+	return res, sr.execute(ctx, inv, opts, sr.interpreterFor(script), res)
 }
 
-// copyDirContents copia el contenido de un directorio a otro
-func copyDirContents(srcDir, destDir string) error {
-	// Asegurarnos de que el directorio destino existe
-	if err := os.MkdirAll(destDir, 0755); err != nil {
-		return err
+// command construye el comando que ejecuta scriptPath con el intérprete indicado
+func (sr *ScriptRunner) command(ctx context.Context, interpreter Interpreter, scriptPath string, args []string) *exec.Cmd {
+	if runtime.GOOS == "windows" && interpreter.Command == sr.interpreterCmd && len(interpreter.Args) == 0 {
+		// Ejecutar script con Git Bash en Windows convirtiendo rutas a formato Unix
+		unixPath := strings.ReplaceAll(scriptPath, "\\", "/")
+		// Pasar los argumentos como parámetros posicionales de bash para evitar problemas de escape
+		fullCommand := fmt.Sprintf("%q \"$@\"", unixPath)
+		cmdArgs := []string{"-c", fullCommand, "--"}
+		cmdArgs = append(cmdArgs, args...)
+		return exec.CommandContext(ctx, interpreter.Command, cmdArgs...)
 	}
 
-	// Recorrer el directorio origen
-	err := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Obtener la ruta relativa
-		relPath, err := filepath.Rel(srcDir, path)
-		if err != nil {
-			return err
-		}
-
-		// Construir la ruta de destino
-		destPath := filepath.Join(destDir, relPath)
-
-		// Si es un directorio, crearlo
-		if info.IsDir() {
-			return os.MkdirAll(destPath, 0755)
-		}
-
-		// Si es un archivo, copiarlo
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
-
-		return os.WriteFile(destPath, data, 0644)
-	})
-
-	return err
-}
-
-// extractScripts extrae todos los scripts del filesystem embebido al directorio de destino
-func extractScripts(fsys embed.FS, baseDir string, targetDir string) error {
-	return fs.WalkDir(fsys, baseDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Crear la ruta de destino (relativa al directorio base)
-		relPath, err := filepath.Rel(baseDir, path)
-		if err != nil {
-			return fmt.Errorf("error al obtener ruta relativa para %s: %w", path, err)
-		}
-		destPath := filepath.Join(targetDir, relPath)
-
-		// Si es un directorio, créalo
-		if d.IsDir() {
-			return os.MkdirAll(destPath, 0755)
-		}
-
-		// Si es un archivo, extráelo
-		content, err := fsys.ReadFile(path)
-		if err != nil {
-			return fmt.Errorf("error al leer el archivo %s: %w", path, err)
-		}
-
-		// Crear el archivo en el directorio de destino
-		if err := os.WriteFile(destPath, content, 0644); err != nil {
-			return fmt.Errorf("error al escribir el archivo %s: %w", destPath, err)
-		}
-		return nil
-	})
+	// En otros sistemas ejecutar directamente
+	name, cmdArgs := interpreter.commandLine(scriptPath, args)
+	return exec.CommandContext(ctx, name, cmdArgs...)
 }
 
 // RunScript es una función de conveniencia para ejecutar scripts bash
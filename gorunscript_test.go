@@ -1,10 +1,12 @@
 package gorunscript
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 // getProjectRoot intenta encontrar la raíz del proyecto desde cualquier ubicación
@@ -45,6 +47,7 @@ func TestRunScript(t *testing.T) {
 
 	// Crear un runner con configuración explícita para los tests
 	runnerForTests := NewBashRunnerWithOptions(projectRoot)
+	runnerForTests.SetWorkspaceRoot(t.TempDir())
 
 	t.Run("Script ejecución exitosa", func(t *testing.T) {
 		exitCode, output, err := runnerForTests.ExecuteScript("test-script", "arg1", "arg2")
@@ -89,6 +92,14 @@ func TestRunScript(t *testing.T) {
 			t.Error("Se esperaba un error, pero no se obtuvo ninguno")
 		}
 
+		var scriptErr *ScriptError
+		if !errors.As(err, &scriptErr) {
+			t.Fatalf("Se esperaba un *ScriptError, se obtuvo: %T %v", err, err)
+		}
+		if scriptErr.ExitCode != 1 || scriptErr.Output != output {
+			t.Errorf("ScriptError con datos inesperados: código %d, salida %q", scriptErr.ExitCode, scriptErr.Output)
+		}
+
 		if !strings.Contains(output, "Error solicitado!") {
 			t.Errorf("Output no contiene el mensaje de error esperado: %s", output)
 		}
@@ -110,8 +121,33 @@ func TestRunScript(t *testing.T) {
 			t.Error("Se esperaba un error, pero no se obtuvo ninguno")
 		}
 
-		if !strings.Contains(err.Error(), "error") {
-			t.Errorf("El mensaje de error no es el esperado: %v", err)
+		if !errors.Is(err, ErrScriptNotFound) {
+			t.Fatalf("Se esperaba ErrScriptNotFound, se obtuvo: %v", err)
+		}
+
+		var notFound *ScriptNotFoundError
+		if !errors.As(err, &notFound) || len(notFound.Available) == 0 {
+			t.Errorf("El error no lista los scripts disponibles: %v", err)
+		}
+	})
+
+	t.Run("Directorio de scripts inexistente", func(t *testing.T) {
+		runner := NewBashRunnerWithOptions(t.TempDir())
+		runner.SetWorkspaceRoot(t.TempDir())
+
+		_, _, err := runner.ExecuteScript("test-script")
+		if !errors.Is(err, ErrExtraction) {
+			t.Errorf("Se esperaba ErrExtraction, se obtuvo: %v", err)
+		}
+	})
+
+	t.Run("Intérprete inexistente", func(t *testing.T) {
+		runner := NewScriptRunner(bash_scripts, "bash_scripts", "interprete-que-no-existe")
+		runner.SetWorkspaceRoot(t.TempDir())
+
+		_, _, err := runner.ExecuteScript("test-script")
+		if !errors.Is(err, ErrInterpreterNotFound) {
+			t.Errorf("Se esperaba ErrInterpreterNotFound, se obtuvo: %v", err)
 		}
 	})
 
@@ -164,3 +200,46 @@ func setupTestEnv(t *testing.T) string {
 
 	return projectRoot
 }
+
+// newTempProject crea un proyecto temporal con los scripts indicados en bash_scripts y devuelve su raíz
+func newTempProject(t *testing.T, scripts map[string]string) string {
+	t.Helper()
+
+	projectRoot := t.TempDir()
+	scriptsDir := filepath.Join(projectRoot, "bash_scripts")
+	if err := os.MkdirAll(scriptsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range scripts {
+		if err := os.WriteFile(filepath.Join(scriptsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return projectRoot
+}
+
+func TestScriptRunnerFS(t *testing.T) {
+	// Scripts en memoria: cualquier fs.FS sirve como origen
+	fsys := fstest.MapFS{
+		"scripts/lib.sh":   {Data: []byte("saludo() { echo \"hola $1\"; }\n")},
+		"scripts/main.sh":  {Data: []byte("#!/bin/bash\nsource lib.sh\nsaludo \"$1\"\n")},
+		"scripts/notes.md": {Data: []byte("no es un script\n")},
+	}
+
+	runner := NewScriptRunner(fsys, "scripts", "bash")
+	runner.SetWorkspaceRoot(t.TempDir())
+
+	exitCode, output, err := runner.ExecuteScript("main", "memoria")
+	if err != nil || exitCode != 0 {
+		t.Fatalf("Ejecución fallida (código %d): %v\n%s", exitCode, err, output)
+	}
+	if !strings.Contains(output, "hola memoria") {
+		t.Errorf("Salida inesperada: %s", output)
+	}
+
+	if _, _, err := runner.ExecuteScript("notes.md"); !errors.Is(err, ErrScriptNotFound) {
+		t.Errorf("Solo deben extraerse scripts, se obtuvo: %v", err)
+	}
+}
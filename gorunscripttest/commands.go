@@ -0,0 +1,265 @@
+// Package gorunscripttest ayuda a escribir tests herméticos de scripts ejecutados con
+// gorunscript. Permite declarar comandos falsos (git, gh, gawk, go...) con salida y código
+// de salida preparados, instalarlos en el PATH de un ScriptRunner y comprobar después con
+// qué argumentos los llamó el script, sin tocar las herramientas reales.
+//
+//	fakes := gorunscripttest.New(t)
+//	fakes.Fake("git").When("describe", "--abbrev=0", "--tags").Stdout("v0.1.0\n")
+//	fakes.Fake("git").When("push").Stderr("rechazado\n").ExitCode(1)
+//	fakes.Install(runner)
+//
+//	runner.ExecuteScript("tag")
+//	fakes.AssertCalled("git", "describe", "--abbrev=0", "--tags")
+//
+// Para scripts que deben operar sobre un repositorio real, NewGitRepo crea un repositorio
+// de trabajo temporal con un origin bare local.
+package gorunscripttest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/cdvelop/gorunscript"
+	"github.com/cdvelop/gorunscript/internal/shim"
+)
+
+// Commands es un conjunto de comandos falsos que comparten un registro de llamadas
+type Commands struct {
+	t       testing.TB
+	dir     string
+	logPath string
+
+	mu       sync.Mutex
+	commands map[string]*Command
+}
+
+// New crea un conjunto vacío en un directorio temporal que se elimina al terminar el test
+func New(t testing.TB) *Commands {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "bin")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatalf("error creando directorio de comandos falsos: %v", err)
+	}
+
+	return &Commands{
+		t:        t,
+		dir:      dir,
+		logPath:  filepath.Join(root, "calls.log"),
+		commands: make(map[string]*Command),
+	}
+}
+
+// Dir devuelve el directorio con los comandos falsos, para anteponerlo al PATH a mano
+func (c *Commands) Dir() string {
+	return c.dir
+}
+
+// Install antepone los comandos falsos al PATH de todas las ejecuciones de runner
+func (c *Commands) Install(runner *gorunscript.ScriptRunner) {
+	runner.PrependPath(c.dir)
+}
+
+// Fake declara el comando falso name, o devuelve el ya declarado. Sin respuestas
+// preparadas el comando no escribe nada y termina con código 0.
+func (c *Commands) Fake(name string) *Command {
+	c.t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cmd, ok := c.commands[name]; ok {
+		return cmd
+	}
+	cmd := &Command{owner: c, name: name}
+	c.commands[name] = cmd
+	cmd.writeLocked()
+	return cmd
+}
+
+// Command es un comando falso
+type Command struct {
+	owner     *Commands
+	name      string
+	responses []*Response
+}
+
+// When prepara la respuesta a las invocaciones cuyos argumentos empiezan por args.
+// Si varias coinciden gana la de prefijo más largo y, a igualdad, la primera declarada.
+// Sin args la respuesta se aplica a cualquier invocación.
+func (cmd *Command) When(args ...string) *Response {
+	c := cmd.owner
+	c.t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r := &Response{cmd: cmd, args: args}
+	cmd.responses = append(cmd.responses, r)
+	cmd.writeLocked()
+	return r
+}
+
+// Returns prepara la respuesta a cualquier invocación del comando
+func (cmd *Command) Returns(stdout string, exitCode int) *Command {
+	cmd.When().Stdout(stdout).ExitCode(exitCode)
+	return cmd
+}
+
+// writeLocked regenera el shim del comando; requiere tener tomado owner.mu
+func (cmd *Command) writeLocked() {
+	c := cmd.owner
+	c.t.Helper()
+
+	ordered := append([]*Response(nil), cmd.responses...)
+	sort.SliceStable(ordered, func(i, j int) bool { return len(ordered[i].args) > len(ordered[j].args) })
+
+	responses := make([]shim.Response, len(ordered))
+	for i, r := range ordered {
+		responses[i] = shim.Response{Args: r.args, Stdout: r.stdout, Stderr: r.stderr, ExitCode: r.exitCode}
+	}
+	if err := shim.Write(c.dir, c.logPath, cmd.name, responses); err != nil {
+		c.t.Fatalf("error escribiendo comando falso %s: %v", cmd.name, err)
+	}
+}
+
+// Response es la respuesta preparada de un comando falso
+type Response struct {
+	cmd      *Command
+	args     []string
+	stdout   string
+	stderr   string
+	exitCode int
+}
+
+// Stdout fija la salida estándar de la respuesta
+func (r *Response) Stdout(s string) *Response {
+	return r.set(func() { r.stdout = s })
+}
+
+// Stderr fija la salida de error de la respuesta
+func (r *Response) Stderr(s string) *Response {
+	return r.set(func() { r.stderr = s })
+}
+
+// ExitCode fija el código de salida de la respuesta
+func (r *Response) ExitCode(code int) *Response {
+	return r.set(func() { r.exitCode = code })
+}
+
+func (r *Response) set(change func()) *Response {
+	c := r.cmd.owner
+	c.t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	change()
+	r.cmd.writeLocked()
+	return r
+}
+
+// Call es una invocación registrada de un comando falso
+type Call struct {
+	Command string
+	Args    []string
+}
+
+// String devuelve la invocación como línea de comandos legible
+func (call Call) String() string {
+	return shim.Call(call).String()
+}
+
+// Calls devuelve todas las invocaciones de los comandos falsos en el orden en que ocurrieron
+func (c *Commands) Calls() []Call {
+	c.t.Helper()
+	calls, err := shim.ReadLog(c.logPath)
+	if err != nil {
+		c.t.Fatalf("error leyendo llamadas: %v", err)
+	}
+
+	result := make([]Call, len(calls))
+	for i, call := range calls {
+		result[i] = Call(call)
+	}
+	return result
+}
+
+// CallsTo devuelve las invocaciones del comando name
+func (c *Commands) CallsTo(name string) []Call {
+	c.t.Helper()
+	var result []Call
+	for _, call := range c.Calls() {
+		if call.Command == name {
+			result = append(result, call)
+		}
+	}
+	return result
+}
+
+// Reset olvida las invocaciones registradas hasta ahora, conservando los comandos
+func (c *Commands) Reset() {
+	c.t.Helper()
+	if err := os.Remove(c.logPath); err != nil && !os.IsNotExist(err) {
+		c.t.Fatalf("error limpiando llamadas: %v", err)
+	}
+}
+
+// AssertCalled comprueba que name se invocó al menos una vez exactamente con args
+func (c *Commands) AssertCalled(name string, args ...string) {
+	c.t.Helper()
+	calls := c.CallsTo(name)
+	for _, call := range calls {
+		if equalArgs(call.Args, args) {
+			return
+		}
+	}
+	c.t.Errorf("se esperaba la llamada %s; llamadas a %s: %v", Call{Command: name, Args: args}, name, calls)
+}
+
+// AssertNotCalled comprueba que name no se invocó nunca
+func (c *Commands) AssertNotCalled(name string) {
+	c.t.Helper()
+	if calls := c.CallsTo(name); len(calls) > 0 {
+		c.t.Errorf("no se esperaban llamadas a %s, se registraron: %v", name, calls)
+	}
+}
+
+// AssertCallCount comprueba cuántas veces se invocó name
+func (c *Commands) AssertCallCount(name string, want int) {
+	c.t.Helper()
+	if calls := c.CallsTo(name); len(calls) != want {
+		c.t.Errorf("se esperaban %d llamadas a %s, se registraron %d: %v", want, name, len(calls), calls)
+	}
+}
+
+// AssertCalls comprueba la secuencia completa de invocaciones, cada una escrita como la
+// devuelve Call.String, p. ej. `git push origin --delete v0.1.0`
+func (c *Commands) AssertCalls(want ...string) {
+	c.t.Helper()
+	calls := c.Calls()
+	got := make([]string, len(calls))
+	for i, call := range calls {
+		got[i] = call.String()
+	}
+	if len(want) == 0 {
+		want = []string{}
+	}
+	if !reflect.DeepEqual(got, want) {
+		c.t.Errorf("llamadas inesperadas:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+// equalArgs compara argumentos tratando nil y vacío como iguales
+func equalArgs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package gorunscripttest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/gorunscript"
+)
+
+// newRunner crea un runner de los scripts embebidos con los comandos falsos instalados
+func newRunner(t *testing.T, fakes *Commands) *gorunscript.ScriptRunner {
+	t.Helper()
+	runner := gorunscript.NewBashRunner()
+	runner.SetWorkspaceRoot(t.TempDir())
+	fakes.Install(runner)
+	return runner
+}
+
+func TestCommands(t *testing.T) {
+	fakes := New(t)
+	fakes.Fake("git").When("describe").Stdout("v0.4.9\n")
+	fakes.Fake("git").When("describe", "--abbrev=0", "--tags").Stdout("v0.5.9\n")
+	fakes.Fake("git").When("push").Stderr("rechazado\n").ExitCode(2)
+	fakes.Fake("gh").Returns("juanin\n", 0)
+
+	runner := newRunner(t, fakes)
+	res, err := runner.RunInline(context.Background(), gorunscript.ExecOptions{},
+		"git describe --abbrev=0 --tags\ngit describe --always\ngh api user\ngit push origin main || echo \"push: $?\"\n")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+	}
+
+	want := "v0.5.9\nv0.4.9\njuanin\npush: 2\n"
+	if res.Stdout != want {
+		t.Errorf("Salida inesperada:\n got: %q\nwant: %q", res.Stdout, want)
+	}
+	if res.Stderr != "rechazado\n" {
+		t.Errorf("Stderr inesperado: %q", res.Stderr)
+	}
+
+	fakes.AssertCalls(
+		"git describe --abbrev=0 --tags",
+		"git describe --always",
+		"gh api user",
+		"git push origin main",
+	)
+	fakes.AssertCalled("gh", "api", "user")
+	fakes.AssertCallCount("git", 3)
+	fakes.AssertNotCalled("go")
+
+	fakes.Reset()
+	fakes.AssertCalls()
+}
+
+func TestAssertionsReportFailures(t *testing.T) {
+	fakes := New(t)
+	fakes.Fake("git")
+
+	rec := &recorder{TB: t}
+	failing := &Commands{t: rec, dir: fakes.dir, logPath: fakes.logPath, commands: fakes.commands}
+	failing.AssertCalled("git", "status")
+	failing.AssertCallCount("git", 1)
+	failing.AssertCalls("git status")
+
+	if rec.failures != 3 {
+		t.Errorf("Se esperaban 3 fallos, se registraron %d", rec.failures)
+	}
+}
+
+// recorder cuenta los fallos en lugar de marcar el test como fallido
+type recorder struct {
+	testing.TB
+	failures int
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures++
+}
+
+// Tests herméticos de scripts embebidos
+
+func TestTagScript(t *testing.T) {
+	fakes := New(t)
+	fakes.Fake("git").When("describe", "--abbrev=0", "--tags").Stdout("v0.0.41\n")
+
+	_, output, err := newRunner(t, fakes).ExecuteScript("tag")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "La siguiente etiqueta será: v0.0.42") {
+		t.Errorf("Salida inesperada: %s", output)
+	}
+	fakes.AssertCalls("git describe --abbrev=0 --tags")
+}
+
+func TestDeltagScript(t *testing.T) {
+	fakes := New(t)
+	fakes.Fake("git")
+
+	_, output, err := newRunner(t, fakes).ExecuteScript("deltag", "v0.1.0", "v0.2.0")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, output)
+	}
+	fakes.AssertCalls(
+		"git tag -d v0.1.0",
+		"git push origin --delete v0.1.0",
+		"git tag -d v0.2.0",
+		"git push origin --delete v0.2.0",
+	)
+}
+
+func TestRemTrackingScript(t *testing.T) {
+	fakes := New(t)
+	fakes.Fake("git").When("branch", "--show-current").Stdout("main\n")
+
+	_, output, err := newRunner(t, fakes).ExecuteScript("rem-tracking", "secreto.env")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, output)
+	}
+	fakes.AssertCalls(
+		"git rm --cached secreto.env",
+		`git commit -m "Dejar de rastrear el archivo secreto.env localmente"`,
+		"git rm --cached secreto.env",
+		`git commit -m "Dejar de rastrear el archivo secreto.env en el seguimiento remoto"`,
+		"git branch --show-current",
+		"git push origin main",
+	)
+}
+
+func TestGoUpgradeScript(t *testing.T) {
+	fakes := New(t)
+	fakes.Fake("go")
+
+	_, output, err := newRunner(t, fakes).ExecuteScript("go-upgrade")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, output)
+	}
+	fakes.AssertCalls("go get -u -t ./...", "go mod tidy")
+}
@@ -0,0 +1,227 @@
+package gorunscripttest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/gorunscript"
+)
+
+// GitRepo es un repositorio de trabajo temporal cuyo remoto "origin" es un repositorio
+// bare local, para probar de principio a fin scripts que hacen push sin acceso a la red.
+// Los comandos git del fixture y de los scripts lanzados con Options ignoran la
+// configuración global y de sistema del usuario.
+type GitRepo struct {
+	t      testing.TB
+	dir    string
+	origin string
+	env    []string
+}
+
+// NewGitRepo crea el repositorio de trabajo con su origin, en la rama main y sin commits.
+// Omite el test si git no está instalado.
+func NewGitRepo(t testing.TB) *GitRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git no está instalado")
+	}
+
+	root := t.TempDir()
+	globalConfig := filepath.Join(root, "gitconfig")
+	if err := os.WriteFile(globalConfig, nil, 0644); err != nil {
+		t.Fatalf("error creando configuración de git: %v", err)
+	}
+
+	r := &GitRepo{
+		t:      t,
+		dir:    filepath.Join(root, "work"),
+		origin: filepath.Join(root, "origin.git"),
+		env: []string{
+			"GIT_CONFIG_GLOBAL=" + globalConfig,
+			"GIT_CONFIG_NOSYSTEM=1",
+			"GIT_AUTHOR_NAME=gorunscript",
+			"GIT_AUTHOR_EMAIL=gorunscript@example.com",
+			"GIT_COMMITTER_NAME=gorunscript",
+			"GIT_COMMITTER_EMAIL=gorunscript@example.com",
+			"GIT_TERMINAL_PROMPT=0",
+		},
+	}
+
+	r.run(root, "init", "--quiet", "--bare", "--initial-branch=main", r.origin)
+	r.run(root, "init", "--quiet", "--initial-branch=main", r.dir)
+	r.Git("remote", "add", "origin", r.origin)
+	return r
+}
+
+// Dir devuelve el directorio del repositorio de trabajo
+func (r *GitRepo) Dir() string {
+	return r.dir
+}
+
+// OriginDir devuelve el directorio del repositorio bare que actúa como origin
+func (r *GitRepo) OriginDir() string {
+	return r.origin
+}
+
+// Env devuelve las variables que aíslan a git de la configuración del usuario
+func (r *GitRepo) Env() []string {
+	return append([]string(nil), r.env...)
+}
+
+// Options devuelve las opciones para ejecutar un script dentro del repositorio de trabajo
+func (r *GitRepo) Options() gorunscript.ExecOptions {
+	return gorunscript.ExecOptions{Dir: r.dir, Env: r.Env()}
+}
+
+// Git ejecuta git en el repositorio de trabajo y devuelve su salida sin espacios finales.
+// Un fallo termina el test.
+func (r *GitRepo) Git(args ...string) string {
+	r.t.Helper()
+	return r.run(r.dir, args...)
+}
+
+// originGit ejecuta git sobre el repositorio bare
+func (r *GitRepo) originGit(args ...string) string {
+	r.t.Helper()
+	return r.run(r.origin, args...)
+}
+
+func (r *GitRepo) run(dir string, args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), r.env...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimRight(string(out), "\n")
+}
+
+// WriteFile escribe name, relativo al repositorio de trabajo, sin añadirlo al índice
+func (r *GitRepo) WriteFile(name, content string) {
+	r.t.Helper()
+	path := filepath.Join(r.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		r.t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// Commit escribe files (nombre relativo y contenido) y crea un commit con todos los cambios.
+// Sin archivos crea un commit vacío.
+func (r *GitRepo) Commit(message string, files map[string]string) {
+	r.t.Helper()
+	for name, content := range files {
+		r.WriteFile(name, content)
+	}
+	r.Git("add", "--all")
+	r.Git("commit", "--quiet", "--allow-empty", "-m", message)
+}
+
+// Tag crea la etiqueta ligera name sobre HEAD
+func (r *GitRepo) Tag(name string) {
+	r.t.Helper()
+	r.Git("tag", name)
+}
+
+// Branch crea la rama name sobre HEAD sin cambiar a ella
+func (r *GitRepo) Branch(name string) {
+	r.t.Helper()
+	r.Git("branch", name)
+}
+
+// Checkout cambia a la rama name
+func (r *GitRepo) Checkout(name string) {
+	r.t.Helper()
+	r.Git("checkout", "--quiet", name)
+}
+
+// Push publica en origin todas las ramas con su upstream y todas las etiquetas
+func (r *GitRepo) Push() {
+	r.t.Helper()
+	r.Git("push", "--quiet", "--all", "--set-upstream", "origin")
+	r.Git("push", "--quiet", "--tags", "origin")
+}
+
+// LocalTags devuelve las etiquetas del repositorio de trabajo ordenadas
+func (r *GitRepo) LocalTags() []string {
+	r.t.Helper()
+	return refNames(r.Git("for-each-ref", "--format=%(refname:short)", "refs/tags"))
+}
+
+// RemoteTags devuelve las etiquetas de origin ordenadas
+func (r *GitRepo) RemoteTags() []string {
+	r.t.Helper()
+	return refNames(r.originGit("for-each-ref", "--format=%(refname:short)", "refs/tags"))
+}
+
+// RemoteBranches devuelve las ramas de origin ordenadas
+func (r *GitRepo) RemoteBranches() []string {
+	r.t.Helper()
+	return refNames(r.originGit("for-each-ref", "--format=%(refname:short)", "refs/heads"))
+}
+
+// RemoteCommitMessages devuelve los asuntos de los commits de branch en origin, del más
+// reciente al más antiguo
+func (r *GitRepo) RemoteCommitMessages(branch string) []string {
+	r.t.Helper()
+	return refNames(r.originGit("log", "--format=%s", "refs/heads/"+branch))
+}
+
+// AssertLocalTags comprueba el conjunto exacto de etiquetas locales
+func (r *GitRepo) AssertLocalTags(want ...string) {
+	r.t.Helper()
+	assertSet(r.t, "etiquetas locales", r.LocalTags(), want)
+}
+
+// AssertRemoteTags comprueba el conjunto exacto de etiquetas de origin
+func (r *GitRepo) AssertRemoteTags(want ...string) {
+	r.t.Helper()
+	assertSet(r.t, "etiquetas remotas", r.RemoteTags(), want)
+}
+
+// AssertRemoteBranches comprueba el conjunto exacto de ramas de origin
+func (r *GitRepo) AssertRemoteBranches(want ...string) {
+	r.t.Helper()
+	assertSet(r.t, "ramas remotas", r.RemoteBranches(), want)
+}
+
+// AssertRemoteCommitMessage comprueba el asunto del último commit de branch en origin
+func (r *GitRepo) AssertRemoteCommitMessage(branch, want string) {
+	r.t.Helper()
+	messages := r.RemoteCommitMessages(branch)
+	if len(messages) == 0 || messages[0] != want {
+		r.t.Errorf("se esperaba el último commit remoto %q en %s, historial: %q", want, branch, messages)
+	}
+}
+
+// refNames divide la salida de git en líneas no vacías
+func refNames(output string) []string {
+	names := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			names = append(names, line)
+		}
+	}
+	return names
+}
+
+// assertSet compara dos conjuntos de nombres sin tener en cuenta el orden
+func assertSet(t testing.TB, what string, got, want []string) {
+	t.Helper()
+	got = append([]string{}, got...)
+	want = append([]string{}, want...)
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s inesperadas:\n got: %q\nwant: %q", what, got, want)
+	}
+}
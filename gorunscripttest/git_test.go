@@ -0,0 +1,157 @@
+package gorunscripttest
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/cdvelop/gorunscript"
+)
+
+// newGitRunner crea un runner de los scripts embebidos que no contacta con GitHub
+func newGitRunner(t *testing.T) *gorunscript.ScriptRunner {
+	t.Helper()
+	fakes := New(t)
+	fakes.Fake("gh").Returns("gorunscript\n", 0)
+	return newRunner(t, fakes)
+}
+
+func TestGitRepo(t *testing.T) {
+	repo := NewGitRepo(t)
+	repo.Commit("inicial", map[string]string{"README.md": "# prueba\n"})
+	repo.Tag("v0.1.0")
+	repo.Branch("dev")
+	repo.Push()
+
+	repo.AssertRemoteBranches("dev", "main")
+	repo.AssertRemoteTags("v0.1.0")
+	repo.AssertLocalTags("v0.1.0")
+	repo.AssertRemoteCommitMessage("main", "inicial")
+
+	repo.Checkout("dev")
+	repo.Commit("en dev", nil)
+	repo.Git("push", "--quiet")
+	repo.AssertRemoteCommitMessage("dev", "en dev")
+	repo.AssertRemoteCommitMessage("main", "inicial")
+
+	if got := repo.RemoteCommitMessages("dev"); len(got) != 2 || got[1] != "inicial" {
+		t.Errorf("Historial remoto inesperado: %q", got)
+	}
+}
+
+func TestDeltagScriptEndToEnd(t *testing.T) {
+	repo := NewGitRepo(t)
+	repo.Commit("inicial", nil)
+	repo.Tag("v0.1.0")
+	repo.Tag("v0.2.0")
+	repo.Tag("v0.3.0")
+	repo.Push()
+
+	_, output, err := newGitRunner(t).ExecuteScriptWithOptions(context.Background(), repo.Options(), "deltag", "v0.1.0", "v0.2.0")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, output)
+	}
+
+	repo.AssertLocalTags("v0.3.0")
+	repo.AssertRemoteTags("v0.3.0")
+}
+
+func TestTagRenameScriptEndToEnd(t *testing.T) {
+	repo := NewGitRepo(t)
+	repo.Commit("inicial", nil)
+	repo.Tag("v0.1.0")
+	repo.Push()
+
+	opts := repo.Options()
+	opts.Stdin = strings.NewReader("v0.1.0\nv1.0.0\n")
+	_, output, err := newGitRunner(t).ExecuteScriptWithOptions(context.Background(), opts, "tag-rename")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, output)
+	}
+
+	repo.AssertLocalTags("v1.0.0")
+	repo.AssertRemoteTags("v1.0.0")
+}
+
+func TestChangeRemoteScriptEndToEnd(t *testing.T) {
+	repo := NewGitRepo(t)
+	newOrigin := filepath.Join(t.TempDir(), "nuevo.git")
+
+	_, output, err := newGitRunner(t).ExecuteScriptWithOptions(context.Background(), repo.Options(), "change-remote", newOrigin)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, output)
+	}
+
+	if got := repo.Git("remote", "get-url", "origin"); got != newOrigin {
+		t.Errorf("Se esperaba el origin %s, se obtuvo %s", newOrigin, got)
+	}
+}
+
+func TestPuScriptEndToEnd(t *testing.T) {
+	repo := NewGitRepo(t)
+	repo.Commit("inicial", map[string]string{"main.go": "package main\n"})
+	repo.Tag("v0.2.7")
+	repo.Push()
+
+	repo.WriteFile("main.go", "package main\n\nfunc main() {}\n")
+
+	res, err := newGitRunner(t).Run(context.Background(), repo.Options(), "pu", "añade main")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+	}
+
+	repo.AssertRemoteCommitMessage("main", "añade main")
+	repo.AssertRemoteTags("v0.2.7", "v0.2.8")
+	repo.AssertRemoteBranches("main")
+
+	if runtime.GOOS != "windows" {
+		var steps []string
+		for _, ev := range res.Events {
+			if ev.Kind == gorunscript.EventStep {
+				steps = append(steps, ev.Message)
+			}
+		}
+		want := []string{"Añadiendo cambios", "Creando commit", "Creando etiqueta v0.2.8", "Subiendo cambios y etiqueta"}
+		if !reflect.DeepEqual(steps, want) {
+			t.Errorf("Pasos inesperados: %q", steps)
+		}
+		if last := res.Events[len(res.Events)-1]; last.Kind != gorunscript.EventResult || last.Message != "v0.2.8" {
+			t.Errorf("Resultado inesperado: %+v", last)
+		}
+	}
+}
+
+func TestTagVerScriptEndToEnd(t *testing.T) {
+	repo := NewGitRepo(t)
+	repo.Commit("inicial", nil)
+	repo.Tag("v0.0.1")
+	repo.Push()
+	repo.Commit("sin publicar", nil)
+	repo.Tag("v0.0.2")
+
+	res, err := newGitRunner(t).Run(context.Background(), repo.Options(), "tag-ver")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+	}
+
+	for _, want := range []string{"local_version: v0.0.2", "remote_version: v0.0.1", "Las versiones son diferentes"} {
+		if !strings.Contains(res.Output, want) {
+			t.Errorf("Se esperaba %q en la salida:\n%s", want, res.Output)
+		}
+	}
+
+	versions, err := gorunscript.DecodeOutputs[struct {
+		Local    string `output:"local_version"`
+		Remote   string `output:"remote_version"`
+		UpToDate bool   `output:"up_to_date"`
+	}](res)
+	if err != nil {
+		t.Fatalf("Error decodificando salidas: %v", err)
+	}
+	if versions.Local != "v0.0.2" || versions.Remote != "v0.0.1" || versions.UpToDate {
+		t.Errorf("Salidas inesperadas: %+v", versions)
+	}
+}
@@ -0,0 +1,61 @@
+package gorunscript
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// inlineScriptName es el nombre con el que se escribe y se informa un script en línea
+const inlineScriptName = "inline.sh"
+
+// ExecuteInline ejecuta el texto source como script bash en el mismo espacio de trabajo
+// preparado que los scripts del runner, de modo que `source functions.sh` y el resto de
+// auxiliares se resuelven igual. Si source empieza con un shebang registrado se usa ese
+// intérprete. Como ExecuteScriptContext, devuelve el código de salida y la salida del
+// comando.
+func (sr *ScriptRunner) ExecuteInline(ctx context.Context, source string, args ...string) (int, string, error) {
+	res, err := sr.RunInline(ctx, ExecOptions{}, source, args...)
+	return res.ExitCode, res.Output, err
+}
+
+// RunInline es el equivalente de Run para texto en línea: aplica las opciones de la
+// llamada y devuelve el resultado detallado
+func (sr *ScriptRunner) RunInline(ctx context.Context, opts ExecOptions, source string, args ...string) (*Result, error) {
+	res := sr.newResult(inlineScriptName, args)
+
+	inv, err := sr.prepare(ctx, opts)
+	if err != nil {
+		return res, err
+	}
+	defer inv.release()
+
+	script := scriptFile{name: inlineScriptName, content: []byte(source)}
+	if err := sr.extract(ctx, inv, script); err != nil {
+		return res, err
+	}
+
+	res.ScriptPath, err = writeInlineScript(inv, source)
+	if err != nil {
+		return res, err
+	}
+
+	return res, sr.execute(ctx, inv, opts, sr.interpreterFor(script), res)
+}
+
+// writeInlineScript escribe source en el espacio de trabajo de inv y devuelve su ruta.
+// Va en un subdirectorio oculto para no mezclarlo con los archivos que el script cree en
+// su directorio de trabajo.
+func writeInlineScript(inv *invocation, source string) (string, error) {
+	dir := filepath.Join(inv.ws.dir, ".inline")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("%w: error creando directorio para script en línea: %w", ErrExtraction, err)
+	}
+
+	scriptPath := filepath.Join(dir, inlineScriptName)
+	if err := os.WriteFile(scriptPath, []byte(source), 0755); err != nil {
+		return "", fmt.Errorf("%w: error al escribir script en línea: %w", ErrExtraction, err)
+	}
+	return scriptPath, nil
+}
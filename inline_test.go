@@ -0,0 +1,64 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExecuteInline(t *testing.T) {
+	projectRoot := newTempProject(t, map[string]string{
+		"helpers.sh": "saludo() { echo \"hola $1\"; }\n",
+	})
+	runner := NewBashRunnerWithOptions(projectRoot)
+	runner.SetWorkspaceRoot(t.TempDir())
+	ctx := context.Background()
+
+	t.Run("Usa los auxiliares del runner", func(t *testing.T) {
+		res, err := runner.RunInline(ctx, ExecOptions{}, "source helpers.sh\nsaludo \"$1\"\necho \"args: $#\"\n", "mundo", "dos")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+
+		if !strings.Contains(res.Stdout, "hola mundo") || !strings.Contains(res.Stdout, "args: 2") {
+			t.Errorf("Salida inesperada: %s", res.Stdout)
+		}
+		if res.Script != inlineScriptName || res.ExitCode != 0 {
+			t.Errorf("Resultado inesperado: %s con código %d", res.Script, res.ExitCode)
+		}
+	})
+
+	t.Run("Usa functions.sh embebido", func(t *testing.T) {
+		res, err := runner.RunInline(ctx, ExecOptions{}, "source functions.sh\nsuccess \"paso listo\"\n")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+		if !strings.Contains(res.Stdout, "\033[0;32mpaso listo\033[0m") {
+			t.Errorf("No se usó success de functions.sh: %q", res.Stdout)
+		}
+	})
+
+	t.Run("Misma firma que ExecuteScript", func(t *testing.T) {
+		exitCode, output, err := runner.ExecuteInline(ctx, "echo \"hola $1\"\necho aviso >&2\nexit 3\n", "mundo")
+		var scriptErr *ScriptError
+		if !errors.As(err, &scriptErr) {
+			t.Fatalf("Se esperaba un *ScriptError, se obtuvo: %v", err)
+		}
+		if exitCode != 3 || !strings.Contains(output, "hola mundo\n") || !strings.Contains(output, "aviso\n") {
+			t.Errorf("Resultado inesperado: código %d, salida %q", exitCode, output)
+		}
+	})
+
+	t.Run("Devuelve ScriptError al fallar", func(t *testing.T) {
+		res, err := runner.RunInline(ctx, ExecOptions{}, "echo fallando >&2\nexit 5\n")
+
+		var scriptErr *ScriptError
+		if !errors.As(err, &scriptErr) || scriptErr.ExitCode != 5 {
+			t.Fatalf("Se esperaba un *ScriptError con código 5, se obtuvo: %v", err)
+		}
+		if res.Stderr != "fallando\n" {
+			t.Errorf("stderr inesperado: %q", res.Stderr)
+		}
+	})
+}
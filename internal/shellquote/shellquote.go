@@ -0,0 +1,10 @@
+// Package shellquote entrecomilla texto para usarlo en scripts de shell POSIX
+package shellquote
+
+import "strings"
+
+// Quote devuelve s entre comillas simples para que el shell lo trate como una sola palabra
+// literal, sin expandir variables, comodines ni sustituciones
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
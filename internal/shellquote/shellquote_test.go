@@ -0,0 +1,23 @@
+package shellquote
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh no disponible")
+	}
+
+	tests := []string{"", "simple", "con espacios", "$HOME `id` *", "it's", "'", "a\nb"}
+	for _, s := range tests {
+		out, err := exec.Command("sh", "-c", "printf '%s' "+Quote(s)).Output()
+		if err != nil {
+			t.Fatalf("Error ejecutando sh para %q: %v", s, err)
+		}
+		if string(out) != s {
+			t.Errorf("Quote(%q): sh devolvió %q", s, out)
+		}
+	}
+}
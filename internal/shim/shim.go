@@ -0,0 +1,155 @@
+// Package shim genera comandos falsos para anteponer al PATH de un script. Cada shim
+// registra su invocación en un archivo de registro compartido y responde con una salida
+// y un código de salida preparados, sin ejecutar el comando real.
+//
+// Los shims son scripts POSIX sh sin extensión, de modo que bash los encuentra por su
+// nombre (git, gh, go...) igual que al comando real, también desde Git Bash en Windows.
+package shim
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cdvelop/gorunscript/internal/shellquote"
+)
+
+// Separadores ASCII del registro: no aparecen en argumentos normales y permiten
+// conservar espacios y saltos de línea de cada argumento sin escapar nada
+const (
+	unitSeparator   = "\x1f" // Separa el comando y cada argumento
+	recordSeparator = "\x1e" // Termina cada invocación
+)
+
+// Response es la respuesta preparada de un shim
+type Response struct {
+	// Args es el prefijo de argumentos al que se aplica la respuesta; vacío coincide con
+	// cualquier invocación. Gana la primera respuesta que coincide.
+	Args []string
+
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Call es una invocación registrada por un shim
+type Call struct {
+	Command string
+	Args    []string
+}
+
+// String devuelve la invocación como línea de comandos legible
+func (c Call) String() string {
+	parts := []string{c.Command}
+	for _, a := range c.Args {
+		if a == "" || strings.ContainsAny(a, " \t\n\"'\\$`") {
+			a = strconv.Quote(a)
+		}
+		parts = append(parts, a)
+	}
+	return strings.Join(parts, " ")
+}
+
+// script es el cuerpo de cada shim. %[1]s es el directorio de respuestas del comando,
+// %[2]s el nombre del comando y %[3]s el archivo de registro, todos ya entrecomillados.
+const script = `#!/bin/sh
+# Generado por gorunscript: registra la llamada y devuelve una respuesta preparada
+us=$(printf '\037')
+rs=$(printf '\036')
+dir=%[1]s
+
+rec=%[2]s
+for a in "$@"; do rec="$rec$us$a"; done
+printf '%%s%%s' "$rec" "$rs" >> %[3]s
+
+args=""
+for a in "$@"; do args="$args$a$us"; done
+
+i=0
+while [ -f "$dir/$i.match" ]; do
+  match=$(cat "$dir/$i.match"; printf x)
+  match=${match%%x}
+  case "$args" in
+    "$match"*)
+      cat "$dir/$i.stdout"
+      cat "$dir/$i.stderr" >&2
+      exit "$(cat "$dir/$i.code")"
+      ;;
+  esac
+  i=$((i + 1))
+done
+exit 0
+`
+
+// Write crea en dir el shim de command, que registra cada invocación en logPath y
+// responde con la primera de responses que coincide. Sin respuesta que coincida, el shim
+// no escribe nada y termina con código 0. Volver a llamar a Write reemplaza las respuestas.
+func Write(dir, logPath, command string, responses []Response) error {
+	if command == "" || strings.ContainsAny(command, `/\`) {
+		return fmt.Errorf("nombre de comando no válido: %q", command)
+	}
+
+	respDir := filepath.Join(dir, ".responses", command)
+	if err := os.RemoveAll(respDir); err != nil {
+		return fmt.Errorf("error limpiando respuestas de %s: %w", command, err)
+	}
+	if err := os.MkdirAll(respDir, 0755); err != nil {
+		return fmt.Errorf("error creando respuestas de %s: %w", command, err)
+	}
+
+	for i, r := range responses {
+		var match string
+		for _, a := range r.Args {
+			match += a + unitSeparator
+		}
+
+		files := map[string]string{
+			"match":  match,
+			"stdout": r.Stdout,
+			"stderr": r.Stderr,
+			"code":   strconv.Itoa(r.ExitCode),
+		}
+		for ext, content := range files {
+			p := filepath.Join(respDir, fmt.Sprintf("%d.%s", i, ext))
+			if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+				return fmt.Errorf("error escribiendo respuesta de %s: %w", command, err)
+			}
+		}
+	}
+
+	// Git Bash en Windows entiende las rutas con barras normales
+	body := fmt.Sprintf(script, shellquote.Quote(filepath.ToSlash(respDir)), shellquote.Quote(command), shellquote.Quote(filepath.ToSlash(logPath)))
+	if err := os.WriteFile(filepath.Join(dir, command), []byte(body), 0755); err != nil {
+		return fmt.Errorf("error escribiendo shim de %s: %w", command, err)
+	}
+	return nil
+}
+
+// ReadLog devuelve las invocaciones registradas en logPath en el orden en que ocurrieron.
+// Un registro inexistente equivale a ninguna invocación.
+func ReadLog(logPath string) ([]Call, error) {
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error leyendo registro de comandos: %w", err)
+	}
+
+	var calls []Call
+	for _, record := range strings.Split(string(data), recordSeparator) {
+		if record == "" {
+			continue
+		}
+		fields := strings.Split(record, unitSeparator)
+		call := Call{Command: fields[0]}
+		if len(fields) > 1 {
+			call.Args = fields[1:]
+		}
+		calls = append(calls, call)
+	}
+	return calls, nil
+}
@@ -0,0 +1,88 @@
+package shim
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestShim(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Los shims se ejecutan con sh; en Windows solo desde Git Bash")
+	}
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "calls.log")
+	responses := []Response{
+		{Args: []string{"tag", "-l"}, Stdout: "v0.1.0\nv0.2.0\n"},
+		{Args: []string{"push"}, Stderr: "rechazado\n", ExitCode: 3},
+		{Stdout: "por defecto\n"},
+	}
+	if err := Write(dir, logPath, "git", responses); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) (string, string, int) {
+		t.Helper()
+		cmd := exec.Command(filepath.Join(dir, "git"), args...)
+		var stderr []byte
+		stdout, err := cmd.Output()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = exitErr.Stderr
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		return string(stdout), string(stderr), cmd.ProcessState.ExitCode()
+	}
+
+	if out, _, code := run("tag", "-l"); out != "v0.1.0\nv0.2.0\n" || code != 0 {
+		t.Errorf("Respuesta inesperada para tag -l: %q (%d)", out, code)
+	}
+	if _, errOut, code := run("push", "origin", "main"); errOut != "rechazado\n" || code != 3 {
+		t.Errorf("Respuesta inesperada para push: %q (%d)", errOut, code)
+	}
+	if out, _, _ := run("tagging"); out != "por defecto\n" {
+		t.Errorf("El prefijo debe coincidir por argumentos completos, se obtuvo %q", out)
+	}
+	run("commit", "-m", "mensaje con espacios\ny salto")
+	run()
+
+	calls, err := ReadLog(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Call{
+		{Command: "git", Args: []string{"tag", "-l"}},
+		{Command: "git", Args: []string{"push", "origin", "main"}},
+		{Command: "git", Args: []string{"tagging"}},
+		{Command: "git", Args: []string{"commit", "-m", "mensaje con espacios\ny salto"}},
+		{Command: "git"},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Registro inesperado:\n got: %q\nwant: %q", calls, want)
+	}
+
+	if got := want[3].String(); got != `git commit -m "mensaje con espacios\ny salto"` {
+		t.Errorf("String inesperado: %s", got)
+	}
+}
+
+func TestReadLogMissing(t *testing.T) {
+	calls, err := ReadLog(filepath.Join(t.TempDir(), "no-existe"))
+	if err != nil || calls != nil {
+		t.Errorf("Se esperaba un registro vacío, se obtuvo %v, %v", calls, err)
+	}
+}
+
+func TestWriteInvalidCommand(t *testing.T) {
+	dir := t.TempDir()
+	if err := Write(dir, filepath.Join(dir, "log"), "../git", nil); err == nil {
+		t.Error("Se esperaba error con un nombre de comando con ruta")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "git")); err == nil {
+		t.Error("No debía crearse ningún shim")
+	}
+}
@@ -0,0 +1,198 @@
+package gorunscript
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// Marcadores de la plantilla de argumentos de un intérprete
+const (
+	ArgScript = "{script}" // Ruta del script
+	ArgArgs   = "{args}"   // Argumentos del script, cada uno como elemento independiente
+)
+
+// Interpreter describe cómo ejecutar un tipo de script
+type Interpreter struct {
+	Command string // Ejecutable del intérprete, p. ej. "bash" o "python3"
+
+	// Args es la plantilla de argumentos. ArgScript se sustituye por la ruta del script y
+	// ArgArgs por sus argumentos; los que no aparezcan se añaden al final en ese orden.
+	Args []string
+
+	// Check es la plantilla de argumentos del modo que analiza el script sin ejecutarlo,
+	// como `bash -n`, con ArgScript en lugar de la ruta. Vacío si el intérprete no lo admite.
+	Check []string
+}
+
+// commandLine construye el ejecutable y los argumentos para ejecutar scriptPath con args
+func (in Interpreter) commandLine(scriptPath string, args []string) (string, []string) {
+	var cmdArgs []string
+	hasScript, hasArgs := false, false
+
+	for _, a := range in.Args {
+		switch a {
+		case ArgScript:
+			hasScript = true
+			cmdArgs = append(cmdArgs, scriptPath)
+		case ArgArgs:
+			hasArgs = true
+			cmdArgs = append(cmdArgs, args...)
+		default:
+			cmdArgs = append(cmdArgs, a)
+		}
+	}
+
+	if !hasScript {
+		cmdArgs = append(cmdArgs, scriptPath)
+	}
+	if !hasArgs {
+		cmdArgs = append(cmdArgs, args...)
+	}
+	return in.Command, cmdArgs
+}
+
+// InterpreterRegistry asocia extensiones de archivo y programas de shebang con intérpretes.
+// Determina qué archivos se extraen como scripts y cómo se ejecuta cada uno. Las
+// modificaciones deben hacerse antes de empezar a ejecutar scripts.
+type InterpreterRegistry struct {
+	extensions []string // Extensiones registradas en orden de búsqueda para nombres sin extensión
+	byExt      map[string]Interpreter
+	byShebang  map[string]Interpreter
+}
+
+// NewInterpreterRegistry crea un registro vacío
+func NewInterpreterRegistry() *InterpreterRegistry {
+	return &InterpreterRegistry{
+		byExt:     make(map[string]Interpreter),
+		byShebang: make(map[string]Interpreter),
+	}
+}
+
+// DefaultInterpreters crea el registro por defecto con shell sobre bash: .sh y .bash con
+// bash, .py con python3 y .ps1 con PowerShell core, además de los shebangs equivalentes y sh.
+func DefaultInterpreters() *InterpreterRegistry {
+	return newDefaultInterpreters(defaultBashCmd())
+}
+
+// newDefaultInterpreters crea el registro por defecto usando shellCmd para los scripts bash
+func newDefaultInterpreters(shellCmd string) *InterpreterRegistry {
+	r := NewInterpreterRegistry()
+
+	shell := Interpreter{Command: shellCmd, Check: []string{"-n", ArgScript}}
+	// ast.parse compila sin escribir __pycache__ junto al script, a diferencia de py_compile
+	pythonCheck := []string{"-c", `import ast, sys; ast.parse(open(sys.argv[1], "rb").read(), sys.argv[1])`, ArgScript}
+	python := Interpreter{Command: "python3", Check: pythonCheck}
+	if runtime.GOOS == "windows" {
+		python.Command = "python"
+	}
+	pwsh := Interpreter{Command: "pwsh", Args: []string{"-NoProfile", "-NonInteractive", "-File", ArgScript, ArgArgs}}
+
+	r.RegisterExtension(".sh", shell)
+	r.RegisterExtension(".bash", shell)
+	r.RegisterExtension(".py", python)
+	r.RegisterExtension(".ps1", pwsh)
+
+	r.RegisterShebang("bash", shell)
+	r.RegisterShebang("sh", Interpreter{Command: "sh", Check: []string{"-n", ArgScript}})
+	r.RegisterShebang("python3", python)
+	r.RegisterShebang("python", python)
+	r.RegisterShebang("pwsh", pwsh)
+
+	return r
+}
+
+// defaultBashCmd devuelve el bash por defecto de la plataforma
+func defaultBashCmd() string {
+	if runtime.GOOS == "windows" {
+		// Usar Git Bash directamente en Windows
+		return `C:\Program Files\Git\bin\bash.exe`
+	}
+	return "bash"
+}
+
+// checkLine construye el ejecutable y los argumentos para analizar scriptPath sin ejecutarlo.
+// ok es false si el intérprete no tiene modo de análisis.
+func (in Interpreter) checkLine(scriptPath string) (string, []string, bool) {
+	if len(in.Check) == 0 {
+		return "", nil, false
+	}
+
+	cmdArgs := make([]string, len(in.Check))
+	for i, a := range in.Check {
+		if a == ArgScript {
+			a = scriptPath
+		}
+		cmdArgs[i] = a
+	}
+	return in.Command, cmdArgs, true
+}
+
+// RegisterExtension asocia la extensión ext (p. ej. ".py") con un intérprete
+func (r *InterpreterRegistry) RegisterExtension(ext string, in Interpreter) {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	if _, ok := r.byExt[ext]; !ok {
+		r.extensions = append(r.extensions, ext)
+	}
+	r.byExt[ext] = in
+}
+
+// RegisterShebang asocia el programa de una línea #! (p. ej. "python3") con un intérprete.
+// Tiene prioridad sobre la extensión cuando el script declara ese shebang.
+func (r *InterpreterRegistry) RegisterShebang(program string, in Interpreter) {
+	r.byShebang[program] = in
+}
+
+// Extensions devuelve las extensiones registradas en orden de búsqueda
+func (r *InterpreterRegistry) Extensions() []string {
+	return append([]string(nil), r.extensions...)
+}
+
+// Handles indica si name tiene una extensión registrada
+func (r *InterpreterRegistry) Handles(name string) bool {
+	_, ok := r.byExt[strings.ToLower(path.Ext(name))]
+	return ok
+}
+
+// Resolve devuelve el intérprete para un script: primero por su shebang, si lo tiene y
+// está registrado, y si no por su extensión
+func (r *InterpreterRegistry) Resolve(name string, content []byte) (Interpreter, bool) {
+	if program := shebangProgram(content); program != "" {
+		if in, ok := r.byShebang[program]; ok {
+			return in, true
+		}
+	}
+	in, ok := r.byExt[strings.ToLower(path.Ext(name))]
+	return in, ok
+}
+
+// shebangProgram extrae el nombre del programa de la línea #!, resolviendo /usr/bin/env
+func shebangProgram(content []byte) string {
+	if !bytes.HasPrefix(content, []byte("#!")) {
+		return ""
+	}
+
+	line, _, _ := bufio.NewReader(bytes.NewReader(content[2:])).ReadLine()
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	program := path.Base(fields[0])
+	if program == "env" {
+		// #!/usr/bin/env [-S] programa [args]
+		program = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") && !strings.Contains(f, "=") {
+				program = path.Base(f)
+				break
+			}
+		}
+	}
+	return program
+}
@@ -0,0 +1,99 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestShebangProgram(t *testing.T) {
+	cases := map[string]string{
+		"#!/bin/bash\necho":               "bash",
+		"#!/usr/bin/env python3\nprint()": "python3",
+		"#!/usr/bin/env -S bash -e\necho": "bash",
+		"#! /bin/sh -eu\n":                "sh",
+		"#!/usr/bin/env LANG=C pwsh\n":    "pwsh",
+		"echo sin shebang\n":              "",
+		"#!\n":                            "",
+	}
+
+	for content, want := range cases {
+		if got := shebangProgram([]byte(content)); got != want {
+			t.Errorf("shebangProgram(%q) = %q, se esperaba %q", content, got, want)
+		}
+	}
+}
+
+func TestInterpreterCommandLine(t *testing.T) {
+	pwsh := Interpreter{Command: "pwsh", Args: []string{"-File", ArgScript, ArgArgs}}
+	name, args := pwsh.commandLine("/tmp/a.ps1", []string{"x", "y"})
+	if name != "pwsh" || !reflect.DeepEqual(args, []string{"-File", "/tmp/a.ps1", "x", "y"}) {
+		t.Errorf("Línea de comando inesperada: %s %v", name, args)
+	}
+
+	plain := Interpreter{Command: "bash", Args: []string{"-e"}}
+	_, args = plain.commandLine("/tmp/a.sh", []string{"x"})
+	if !reflect.DeepEqual(args, []string{"-e", "/tmp/a.sh", "x"}) {
+		t.Errorf("Se esperaba el script y los argumentos al final: %v", args)
+	}
+}
+
+func TestMixedInterpreters(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hola.sh":     {Data: []byte("#!/bin/bash\necho \"bash $BASH_VERSION\" | cut -c1-4\n")},
+		"posix.sh":    {Data: []byte("#!/bin/sh\n[ -z \"$BASH_VERSION\" ] && echo posix\n")},
+		"datos.txt":   {Data: []byte("contenido de datos\n")},
+		"ignorado.md": {Data: []byte("no es un script\n")},
+		"calc.py":     {Data: []byte("import sys\nprint('python', int(sys.argv[1]) * 2)\n")},
+	}
+
+	runner := NewScriptRunner(fsys, ".", "bash")
+	runner.SetWorkspaceRoot(t.TempDir())
+	runner.Interpreters().RegisterExtension(".txt", Interpreter{Command: "cat"})
+	ctx := context.Background()
+
+	t.Run("Bash por extensión", func(t *testing.T) {
+		res, err := runner.Run(ctx, ExecOptions{}, "hola")
+		if err != nil || strings.TrimSpace(res.Stdout) != "bash" {
+			t.Errorf("Salida inesperada %q: %v", res.Stdout, err)
+		}
+	})
+
+	t.Run("Shebang con prioridad sobre la extensión", func(t *testing.T) {
+		if _, err := exec.LookPath("sh"); err != nil {
+			t.Skip("sh no disponible")
+		}
+		res, err := runner.Run(ctx, ExecOptions{}, "posix")
+		if err != nil || res.Interpreter != "sh" || strings.TrimSpace(res.Stdout) != "posix" {
+			t.Errorf("Se esperaba ejecución con sh, se obtuvo %q (%q): %v", res.Interpreter, res.Stdout, err)
+		}
+	})
+
+	t.Run("Extensión registrada por el usuario", func(t *testing.T) {
+		res, err := runner.Run(ctx, ExecOptions{}, "datos")
+		if err != nil || res.Stdout != "contenido de datos\n" {
+			t.Errorf("Salida inesperada %q: %v", res.Stdout, err)
+		}
+	})
+
+	t.Run("Python", func(t *testing.T) {
+		if _, err := exec.LookPath("python3"); err != nil {
+			t.Skip("python3 no disponible")
+		}
+		res, err := runner.Run(ctx, ExecOptions{}, "calc", "21")
+		if err != nil || strings.TrimSpace(res.Stdout) != "python 42" {
+			t.Errorf("Salida inesperada %q: %v\n%s", res.Stdout, err, res.Stderr)
+		}
+	})
+
+	t.Run("Extensión sin intérprete no se extrae", func(t *testing.T) {
+		_, err := runner.Run(ctx, ExecOptions{}, "ignorado.md")
+		if !errors.Is(err, ErrScriptNotFound) {
+			t.Errorf("Se esperaba ErrScriptNotFound, se obtuvo: %v", err)
+		}
+	})
+}
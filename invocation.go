@@ -0,0 +1,309 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// invocation agrupa los recursos preparados para una ejecución: el espacio de trabajo
+// aislado y el directorio de caché con los scripts que necesita
+type invocation struct {
+	ws         *workspace
+	keep       bool         // Conservar el espacio de trabajo al liberarlo
+	cacheRoot  string       // Raíz de la caché de scripts extraídos
+	scriptsDir string       // Directorio de caché con los scripts, antepuesto al PATH; lo fija extract
+	files      []scriptFile // Scripts del catálogo combinado
+	workDir    string       // Directorio de trabajo efectivo del proceso
+}
+
+// newResult crea el resultado inicial de una ejecución que aún no ha empezado
+func (sr *ScriptRunner) newResult(scriptName string, args []string) *Result {
+	return &Result{
+		Script:      scriptName,
+		Interpreter: sr.interpreterCmd,
+		Args:        append([]string(nil), args...),
+		ExitCode:    1,
+	}
+}
+
+// prepare crea el espacio de trabajo de la invocación y lee el catálogo de scripts.
+// Los scripts no se escriben en disco hasta llamar a extract.
+func (sr *ScriptRunner) prepare(ctx context.Context, opts ExecOptions) (*invocation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error ejecutando script: %w", err)
+	}
+
+	workDir, err := opts.workDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// Crear un espacio de trabajo aislado para esta ejecución
+	root, err := sr.getWorkspaceRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := newWorkspace(root)
+	if err != nil {
+		return nil, err
+	}
+	inv := &invocation{ws: ws, keep: !sr.cleanScripts, cacheRoot: filepath.Join(root, cacheDirName), workDir: ws.dir}
+	// Como la recolección de espacios de trabajo, la limpieza de la caché no impide ejecutar
+	_ = pruneScriptCache(inv.cacheRoot, sr.cacheMaxAge)
+	if workDir != "" {
+		inv.workDir = workDir
+	}
+
+	inv.files, _, err = sr.resolveScripts()
+	if err != nil {
+		inv.release()
+		return nil, fmt.Errorf("%w: %w", ErrExtraction, err)
+	}
+
+	return inv, nil
+}
+
+// extract deja en la caché solo los scripts que necesitan roots: ellos mismos y sus
+// dependencias estáticas (`source`, `.`, `bash`, `sh` e invocaciones por PATH). Reutiliza
+// la caché si el contenido no cambió. Su directorio se antepone al PATH para que
+// `source functions.sh` se resuelva aunque el directorio de trabajo sea otro.
+// Con SetValidateBeforeRun, además se valida la sintaxis de los scripts extraídos.
+func (sr *ScriptRunner) extract(ctx context.Context, inv *invocation, roots ...scriptFile) error {
+	files, err := dependencyClosure(inv.files, roots, sr.interpreters)
+	if err != nil {
+		return err
+	}
+
+	inv.scriptsDir, err = prepareScriptCache(inv.files, files, inv.cacheRoot)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExtraction, err)
+	}
+
+	if !sr.validateBeforeRun {
+		return nil
+	}
+	diagnostics, err := sr.checkScripts(ctx, inv.scriptsDir, files)
+	if err != nil {
+		return err
+	}
+	if len(diagnostics) > 0 {
+		return &ValidationError{Diagnostics: diagnostics}
+	}
+	return nil
+}
+
+// release libera el espacio de trabajo, eliminándolo si así se ha configurado
+func (inv *invocation) release() {
+	inv.ws.release(inv.keep)
+}
+
+// interpreterFor resuelve el intérprete de un script, con el del runner como respaldo
+func (sr *ScriptRunner) interpreterFor(script scriptFile) Interpreter {
+	interpreter, ok := sr.interpreters.Resolve(script.name, script.content)
+	if !ok {
+		interpreter = Interpreter{Command: sr.interpreterCmd}
+	}
+	return interpreter
+}
+
+// execute ejecuta res.ScriptPath con el intérprete indicado y completa res con la salida,
+// la terminación y los tiempos
+func (sr *ScriptRunner) execute(ctx context.Context, inv *invocation, opts ExecOptions, interpreter Interpreter, res *Result) error {
+	proc, err := sr.start(ctx, inv, opts, interpreter, res, nil)
+	if err != nil {
+		return err
+	}
+	return proc.wait()
+}
+
+// process es un script en ejecución iniciado por start
+type process struct {
+	ctx      context.Context
+	cancel   context.CancelCauseFunc // Libera el contexto derivado para Expect
+	cmd      *exec.Cmd
+	capture  *streamCapture
+	events   *eventReader // nil si la plataforma no admite eventos
+	outputs  string       // Archivo de salidas de ResultFileEnv
+	pty      *ptySession  // nil salvo en modo PTY
+	expect   *expecter    // nil si no hay reglas de Expect
+	stopKill func()
+	res      *Result
+}
+
+// start inicia res.ScriptPath con el intérprete indicado. Si stdout no es nil, la salida
+// estándar del proceso se conecta directamente a ese archivo en lugar de capturarse.
+func (sr *ScriptRunner) start(ctx context.Context, inv *invocation, opts ExecOptions, interpreter Interpreter, res *Result, stdout *os.File) (*process, error) {
+	res.Interpreter = interpreter.Command
+
+	// Con Expect las respuestas son la entrada, y una pregunta inesperada cancela el contexto
+	var exp *expecter
+	cancel := func(error) {}
+	if opts.Expect != nil {
+		if opts.Stdin != nil {
+			return nil, errors.New("error: Expect y Stdin no pueden usarse a la vez")
+		}
+		var err error
+		if exp, err = newExpecter(opts.Expect); err != nil {
+			return nil, err
+		}
+		ctx, cancel = context.WithCancelCause(ctx)
+		exp.fail = cancel
+	}
+
+	cmd := sr.command(ctx, interpreter, res.ScriptPath, res.Args)
+
+	// Ejecutar en un grupo de procesos propio para poder terminar también a los nietos
+	stopKill := configureProcessGroup(cmd, sr.killGrace)
+
+	// Recursos a liberar si el proceso no llega a iniciarse
+	aborts := []func(){stopKill, func() { cancel(nil) }}
+	abort := func(err error) (*process, error) {
+		for _, f := range aborts {
+			f()
+		}
+		return nil, err
+	}
+
+	// Establecer el directorio de trabajo solicitado o, por defecto, el espacio de trabajo aislado
+	cmd.Dir = inv.workDir
+
+	// Configurar variables de entorno para asegurar la estabilidad
+	cmd.Env = opts.buildEnv(inv.scriptsDir, sr.pathDirs)
+	cmd.Stdin = opts.Stdin
+
+	// Ejecutar transmitiendo y capturando la salida
+	capture := newStreamCapture(opts.Streams)
+	cmd.Stdout = capture.stdoutWriter()
+	if stdout != nil {
+		cmd.Stdout = stdout
+	}
+	cmd.Stderr = capture.stderrWriter()
+
+	// Archivo en el que el script publica sus salidas con nombre
+	outputs, err := newResultFile(inv.ws.dir)
+	if err != nil {
+		return abort(fmt.Errorf("%w: %w", ErrOutputs, err))
+	}
+	aborts = append(aborts, func() { _ = os.Remove(outputs) })
+	cmd.Env = append(cmd.Env, ResultFileEnv+"="+outputs)
+
+	// En modo PTY la terminal sustituye a stdin, stdout y stderr
+	var pty *ptySession
+	if opts.PTY != nil {
+		var output io.Writer = capture.stdoutWriter()
+		if exp != nil {
+			output = io.MultiWriter(output, exp)
+		}
+		pty, err = attachPTY(cmd, opts, output)
+		if err != nil {
+			return abort(err)
+		}
+		aborts = append(aborts, pty.abort)
+		if exp != nil {
+			exp.input = pty.master
+		}
+	}
+
+	// Sin terminal, Expect observa ambas salidas y responde por una tubería propia
+	var stdinReader *os.File
+	if exp != nil && pty == nil {
+		r, w, err := os.Pipe()
+		if err != nil {
+			return abort(fmt.Errorf("error creando entrada para Expect: %w", err))
+		}
+		aborts = append(aborts, func() { _ = r.Close(); _ = w.Close() })
+		stdinReader = r
+		cmd.Stdin = r
+		cmd.Stdout = io.MultiWriter(cmd.Stdout, exp)
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, exp)
+		exp.input = w
+		exp.closeInput = func() { _ = w.Close() }
+	}
+
+	// Canal de eventos en vivo, independiente de stdout y stderr
+	events, err := attachEvents(cmd, opts.OnEvent)
+	if err != nil {
+		return abort(fmt.Errorf("error creando canal de eventos: %w", err))
+	}
+	if events != nil {
+		aborts = append(aborts, events.abort)
+	}
+
+	res.StartedAt = time.Now()
+	if err := cmd.Start(); err != nil {
+		res.Duration = time.Since(res.StartedAt)
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+			return abort(fmt.Errorf("%w: %s: %w", ErrInterpreterNotFound, res.Interpreter, err))
+		}
+		return abort(fmt.Errorf("error iniciando script: %w", err))
+	}
+
+	if events != nil {
+		events.start()
+	}
+	if pty != nil {
+		pty.start()
+	}
+	if stdinReader != nil {
+		// El extremo de lectura solo debe quedar abierto en el proceso hijo
+		_ = stdinReader.Close()
+	}
+	if exp != nil {
+		exp.start()
+	}
+
+	return &process{ctx: ctx, cancel: cancel, cmd: cmd, capture: capture, events: events, outputs: outputs, pty: pty, expect: exp, stopKill: stopKill, res: res}, nil
+}
+
+// wait espera a que el proceso termine y completa su resultado
+func (p *process) wait() error {
+	err := p.cmd.Wait()
+	p.stopKill()
+	defer p.cancel(nil)
+
+	res := p.res
+	res.Duration = time.Since(res.StartedAt)
+
+	if p.pty != nil {
+		p.pty.finish()
+	}
+	if p.expect != nil {
+		res.Expects = p.expect.finish()
+	}
+	out := p.capture.finish()
+	res.Output = out.combined
+	res.Stdout = out.stdout
+	res.Stderr = out.stderr
+	res.Report = ParseReport(out.combined)
+	if p.events != nil {
+		res.Events = p.events.finish()
+	}
+	res.ExitCode = p.cmd.ProcessState.ExitCode()
+	res.Signal = exitSignal(p.cmd.ProcessState)
+	outputs, outputsErr := readResultFile(p.outputs)
+	res.Outputs = outputs
+
+	// Manejar errores: interrupción por contexto o código distinto de cero
+	if err != nil {
+		if p.ctx.Err() != nil {
+			// La causa es el error del contexto del llamador o el de Expect
+			return newScriptError(res, context.Cause(p.ctx))
+		}
+		return newScriptError(res, err)
+	}
+
+	// Unas salidas ilegibles solo invalidan una ejecución que por lo demás fue correcta
+	if outputsErr != nil {
+		return fmt.Errorf("%w: %s: %w", ErrOutputs, res.Script, outputsErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package gorunscript
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Nombres de las capas que crean los constructores
+const (
+	LayerEmbedded = "embedded" // Scripts embebidos en el paquete
+	LayerProject  = "project"  // bash_scripts del proyecto en disco
+	LayerUser     = "user"     // Scripts del usuario en su directorio de configuración
+	LayerBase     = "base"     // Origen indicado en NewScriptRunner
+)
+
+// scriptLayer es un origen de scripts dentro de la pila de capas del runner
+type scriptLayer struct {
+	name    string
+	fsys    fs.FS
+	baseDir string
+}
+
+// ScriptInfo describe un script del catálogo combinado y la capa de la que proviene
+type ScriptInfo struct {
+	Name  string
+	Layer string
+}
+
+// ShadowedScript describe un script definido en varias capas
+type ShadowedScript struct {
+	Name     string   // Nombre del script
+	Layer    string   // Capa cuya versión se usa
+	Shadowed []string // Capas ocultas, de mayor a menor precedencia
+}
+
+// AddLayer añade un origen de scripts con mayor precedencia que todos los anteriores.
+// Un script presente en esta capa oculta al del mismo nombre en las capas previas, lo que
+// permite que varios módulos aporten scripts a un mismo runner sin copiar archivos.
+func (sr *ScriptRunner) AddLayer(name string, fsys fs.FS, baseDir string) {
+	sr.layers = append(sr.layers, scriptLayer{name: name, fsys: fsys, baseDir: baseDir})
+}
+
+// AddUserLayer añade como capa de máxima precedencia el directorio de scripts del usuario,
+// <UserConfigDir>/gorunscript/bash_scripts (~/.config/gorunscript/bash_scripts en Linux).
+// Si el directorio no existe no se añade nada y devuelve false.
+func (sr *ScriptRunner) AddUserLayer() (bool, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return false, fmt.Errorf("error obteniendo directorio de configuración: %w", err)
+	}
+
+	dir := filepath.Join(configDir, "gorunscript", "bash_scripts")
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return false, nil
+	}
+
+	sr.AddLayer(LayerUser, os.DirFS(dir), ".")
+	return true, nil
+}
+
+// Scripts devuelve el catálogo combinado ordenado por nombre con la capa de cada script
+func (sr *ScriptRunner) Scripts() ([]ScriptInfo, error) {
+	files, _, err := sr.resolveScripts()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]ScriptInfo, len(files))
+	for i, f := range files {
+		infos[i] = ScriptInfo{Name: f.name, Layer: f.layer}
+	}
+	return infos, nil
+}
+
+// ShadowedScripts devuelve los scripts definidos en más de una capa
+func (sr *ScriptRunner) ShadowedScripts() ([]ShadowedScript, error) {
+	files, definedIn, err := sr.resolveScripts()
+	if err != nil {
+		return nil, err
+	}
+
+	var shadowed []ShadowedScript
+	for _, f := range files {
+		layers := definedIn[f.name]
+		if len(layers) > 1 {
+			shadowed = append(shadowed, ShadowedScript{Name: f.name, Layer: layers[0], Shadowed: layers[1:]})
+		}
+	}
+	return shadowed, nil
+}
+
+// resolveScripts combina las capas por nombre: gana la última capa añadida que define cada
+// script. Devuelve los scripts ganadores ordenados y, por nombre, las capas que lo definen
+// de mayor a menor precedencia.
+func (sr *ScriptRunner) resolveScripts() ([]scriptFile, map[string][]string, error) {
+	winners := make(map[string]scriptFile)
+	definedIn := make(map[string][]string)
+
+	for i := len(sr.layers) - 1; i >= 0; i-- {
+		layer := sr.layers[i]
+		files, err := readScriptSet(layer.fsys, layer.baseDir, sr.interpreters)
+		if err != nil {
+			return nil, nil, fmt.Errorf("capa %s: %w", layer.name, err)
+		}
+
+		for _, f := range files {
+			definedIn[f.name] = append(definedIn[f.name], layer.name)
+			if _, ok := winners[f.name]; !ok {
+				f.layer = layer.name
+				winners[f.name] = f
+			}
+		}
+	}
+
+	merged := make([]scriptFile, 0, len(winners))
+	for _, f := range winners {
+		merged = append(merged, f)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].name < merged[j].name })
+
+	return merged, definedIn, nil
+}
@@ -0,0 +1,73 @@
+package gorunscript
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLayeredSources(t *testing.T) {
+	base := fstest.MapFS{
+		"functions.sh": {Data: []byte("origen() { echo base; }\n")},
+		"pu.sh":        {Data: []byte("#!/bin/bash\nsource functions.sh\necho \"pu base $(origen)\"\n")},
+		"tag.sh":       {Data: []byte("#!/bin/bash\necho tag base\n")},
+	}
+	project := fstest.MapFS{
+		"bash_scripts/pu.sh": {Data: []byte("#!/bin/bash\nsource functions.sh\necho \"pu project $(origen)\"\n")},
+	}
+	user := fstest.MapFS{
+		"pu.sh":     {Data: []byte("#!/bin/bash\necho pu user\n")},
+		"extra.sh":  {Data: []byte("#!/bin/bash\necho extra user\n")},
+		"README.md": {Data: []byte("ignorado\n")},
+	}
+
+	runner := NewScriptRunner(base, ".", "bash")
+	runner.SetWorkspaceRoot(t.TempDir())
+	runner.AddLayer(LayerProject, project, "bash_scripts")
+
+	t.Run("La capa superior oculta a la inferior", func(t *testing.T) {
+		res, err := runner.Run(context.Background(), ExecOptions{}, "pu")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+		if !strings.Contains(res.Stdout, "pu project base") {
+			t.Errorf("Se esperaba la versión del proyecto usando helpers de la base: %s", res.Stdout)
+		}
+		if res.Layer != LayerProject {
+			t.Errorf("Se esperaba la capa %q, se obtuvo %q", LayerProject, res.Layer)
+		}
+	})
+
+	runner.AddLayer(LayerUser, user, ".")
+
+	t.Run("Catálogo combinado con capa de origen", func(t *testing.T) {
+		scripts, err := runner.Scripts()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []ScriptInfo{
+			{Name: "extra.sh", Layer: LayerUser},
+			{Name: "functions.sh", Layer: LayerBase},
+			{Name: "pu.sh", Layer: LayerUser},
+			{Name: "tag.sh", Layer: LayerBase},
+		}
+		if !reflect.DeepEqual(scripts, want) {
+			t.Errorf("Catálogo inesperado:\n%v\nse esperaba:\n%v", scripts, want)
+		}
+	})
+
+	t.Run("Scripts ocultos", func(t *testing.T) {
+		shadowed, err := runner.ShadowedScripts()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []ShadowedScript{{Name: "pu.sh", Layer: LayerUser, Shadowed: []string{LayerProject, LayerBase}}}
+		if !reflect.DeepEqual(shadowed, want) {
+			t.Errorf("Scripts ocultos inesperados: %v", shadowed)
+		}
+	})
+}
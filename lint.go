@@ -0,0 +1,243 @@
+package gorunscript
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Severity es la gravedad de un problema encontrado por Lint
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // El script fallará al llegar a ese punto
+	SeverityWarning Severity = "warning" // Probablemente un error, o funciona por casualidad
+	SeverityInfo    Severity = "info"    // Funciona, pero depende del entorno de gorunscript
+)
+
+// Reglas que aplica Lint
+const (
+	RuleUnresolvedSource  = "unresolved-source"  // source/bash de un script que no está en el catálogo
+	RuleUnresolvedCommand = "unresolved-command" // Script invocado por nombre que no está en el catálogo ni en el PATH
+	RuleEmptyScript       = "empty-script"       // Script sin ningún comando
+	RuleDuplicateFunction = "duplicate-function" // Función definida en varios archivos que se cargan juntos
+	RuleMissingShebang    = "missing-shebang"    // Script ejecutable sin línea #!
+)
+
+// LintIssue es un problema encontrado en el catálogo de scripts
+type LintIssue struct {
+	Severity Severity
+	Script   string // Nombre del script con extensión
+	Line     int    // Línea del problema empezando en 1; 0 si afecta a todo el script
+	Rule     string
+	Message  string
+}
+
+func (i LintIssue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s [%s]", i.Script, i.Severity, i.Message, i.Rule)
+	}
+	return fmt.Sprintf("%s:%d: %s: %s [%s]", i.Script, i.Line, i.Severity, i.Message, i.Rule)
+}
+
+// functionDefinition reconoce `nombre() {` y `function nombre {`
+var functionDefinition = regexp.MustCompile(`^\s*(?:function\s+([A-Za-z_][\w:.-]*)\s*(?:\(\s*\))?|([A-Za-z_][\w:.-]*)\s*\(\s*\))\s*\{?`)
+
+// lookPath busca un ejecutable en el PATH del sistema; reemplazable en tests
+var lookPath = exec.LookPath
+
+// Lint revisa estáticamente los scripts de shell del catálogo combinado y devuelve los
+// problemas ordenados por script y línea: referencias a scripts inexistentes, scripts
+// vacíos, funciones duplicadas entre archivos cargados con source y shebangs ausentes.
+// Al igual que la resolución de dependencias, no sigue referencias construidas con variables.
+func (sr *ScriptRunner) Lint() ([]LintIssue, error) {
+	files, _, err := sr.resolveScripts()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]scriptFile, len(files))
+	for _, f := range files {
+		byName[f.name] = f
+	}
+
+	// Los scripts que otros cargan con source son bibliotecas: no necesitan shebang
+	libraries := make(map[string]bool)
+	refs := make(map[string][]scriptRef)
+	for _, f := range files {
+		if !isShellScript(f) {
+			continue
+		}
+		refs[f.name] = parseScriptRefs(f.content, sr.interpreters)
+		for _, ref := range refs[f.name] {
+			if ref.kind == refSource {
+				libraries[ref.name] = true
+			}
+		}
+	}
+
+	var issues []LintIssue
+	duplicates := make(map[string]bool) // Una misma definición se alcanza desde varios scripts
+
+	for _, f := range files {
+		if !isShellScript(f) {
+			continue
+		}
+
+		if isEmptyScript(f.content) {
+			issues = append(issues, LintIssue{
+				Severity: SeverityWarning, Script: f.name, Rule: RuleEmptyScript,
+				Message: "el script no contiene ningún comando",
+			})
+			continue
+		}
+
+		if !bytes.HasPrefix(f.content, []byte("#!")) && !libraries[f.name] {
+			issues = append(issues, LintIssue{
+				Severity: SeverityWarning, Script: f.name, Line: 1, Rule: RuleMissingShebang,
+				Message: "falta la línea #!; el intérprete depende de cómo se invoque",
+			})
+		}
+
+		checked := make(map[string]bool)
+		for _, ref := range refs[f.name] {
+			if ref.kind == refCheck {
+				checked[ref.name] = true
+			}
+		}
+		for _, ref := range refs[f.name] {
+			if issue, ok := lintRef(f.name, ref, byName, checked[ref.name]); ok {
+				issues = append(issues, issue)
+			}
+		}
+
+		for _, issue := range duplicateFunctions(f, byName, refs) {
+			key := fmt.Sprintf("%s:%d", issue.Script, issue.Line)
+			if !duplicates[key] {
+				duplicates[key] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Script != issues[j].Script {
+			return issues[i].Script < issues[j].Script
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues, nil
+}
+
+// lintRef comprueba que la referencia de script a otro script pueda resolverse. checked
+// indica si script comprueba con `command -v` que el referenciado existe.
+func lintRef(script string, ref scriptRef, byName map[string]scriptFile, checked bool) (LintIssue, bool) {
+	issue := LintIssue{Script: script, Line: ref.line}
+
+	if ref.required() {
+		if _, ok := byName[ref.name]; ok {
+			return issue, false
+		}
+		issue.Severity = SeverityError
+		issue.Rule = RuleUnresolvedSource
+		issue.Message = fmt.Sprintf("'%s' no existe en el catálogo", ref.name)
+		return issue, true
+	}
+
+	issue.Rule = RuleUnresolvedCommand
+	if _, ok := byName[ref.name]; ok {
+		if _, err := lookPath(ref.name); err == nil {
+			return issue, false
+		}
+		issue.Severity = SeverityInfo
+		issue.Message = fmt.Sprintf("'%s' solo está en el PATH cuando se ejecuta con gorunscript", ref.name)
+		return issue, true
+	}
+	if _, err := lookPath(ref.name); err == nil {
+		return issue, false
+	}
+
+	issue.Severity = SeverityError
+	issue.Message = fmt.Sprintf("'%s' no está en el catálogo ni en el PATH", ref.name)
+	if checked {
+		// El script comprueba su existencia antes de usarlo
+		issue.Severity = SeverityWarning
+	}
+	return issue, true
+}
+
+// duplicateFunctions informa de las funciones de f, o de los archivos que carga con source,
+// que ya estaban definidas por otro archivo cargado antes
+func duplicateFunctions(f scriptFile, byName map[string]scriptFile, refs map[string][]scriptRef) []LintIssue {
+	var issues []LintIssue
+	definedIn := make(map[string]string)
+	visited := make(map[string]bool)
+
+	var visit func(script scriptFile)
+	visit = func(script scriptFile) {
+		if visited[script.name] {
+			return
+		}
+		visited[script.name] = true
+
+		// Las funciones quedan definidas en el orden en que bash encuentra cada source
+		sources := make(map[int][]string)
+		for _, ref := range refs[script.name] {
+			if ref.kind == refSource {
+				sources[ref.line] = append(sources[ref.line], ref.name)
+			}
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(script.content))
+		scanner.Buffer(make([]byte, 0, 64*1024), len(script.content)+1)
+		for n := 1; scanner.Scan(); n++ {
+			if m := functionDefinition.FindStringSubmatch(scanner.Text()); m != nil {
+				name := m[1] + m[2]
+				if first, ok := definedIn[name]; ok && first != script.name {
+					issues = append(issues, LintIssue{
+						Severity: SeverityWarning, Script: script.name, Line: n, Rule: RuleDuplicateFunction,
+						Message: fmt.Sprintf("la función %s ya está definida en %s", name, first),
+					})
+				} else if !ok {
+					definedIn[name] = script.name
+				}
+			}
+			for _, dep := range sources[n] {
+				if lib, ok := byName[dep]; ok {
+					visit(lib)
+				}
+			}
+		}
+	}
+	visit(f)
+
+	return issues
+}
+
+// isShellScript indica si f es un script de shell, por su extensión o su shebang
+func isShellScript(f scriptFile) bool {
+	switch shebangProgram(f.content) {
+	case "bash", "sh":
+		return true
+	case "":
+		ext := strings.ToLower(path.Ext(f.name))
+		return ext == ".sh" || ext == ".bash"
+	}
+	return false
+}
+
+// isEmptyScript indica si content solo contiene espacios, comentarios o el shebang
+func isEmptyScript(content []byte) bool {
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
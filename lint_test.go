@@ -0,0 +1,75 @@
+package gorunscript
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLint(t *testing.T) {
+	// Solo "git-fake.sh" existe en el PATH del sistema durante el test
+	original := lookPath
+	lookPath = func(file string) (string, error) {
+		if file == "git-fake.sh" {
+			return "/usr/bin/git-fake.sh", nil
+		}
+		return "", errors.New("no encontrado")
+	}
+	defer func() { lookPath = original }()
+
+	fsys := fstest.MapFS{
+		"functions.sh": {Data: []byte("success() { echo ok; }\nerror() { echo mal; }\n")},
+		"extra.sh":     {Data: []byte("source functions.sh\nfunction success {\n  echo otra\n}\n")},
+		"main.sh": {Data: []byte("#!/bin/bash\nsource functions.sh\nsource extra.sh\nsource falta.sh\n" +
+			"helper.sh\ngit-fake.sh status\nnoexiste.sh\n" +
+			"if command -v opcional.sh >/dev/null; then\n  opcional.sh\nfi\n")},
+		"helper.sh": {Data: []byte("echo sin shebang\n")},
+		"vacio.sh":  {Data: []byte("#!/bin/bash\n# nada\n\n")},
+		"tool.py":   {Data: []byte("print('no es shell')\n")},
+	}
+
+	runner := NewScriptRunner(fsys, ".", "bash")
+	issues, err := runner.Lint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []LintIssue{
+		{Severity: SeverityWarning, Script: "extra.sh", Line: 2, Rule: RuleDuplicateFunction, Message: "la función success ya está definida en functions.sh"},
+		{Severity: SeverityWarning, Script: "helper.sh", Line: 1, Rule: RuleMissingShebang, Message: "falta la línea #!; el intérprete depende de cómo se invoque"},
+		{Severity: SeverityError, Script: "main.sh", Line: 4, Rule: RuleUnresolvedSource, Message: "'falta.sh' no existe en el catálogo"},
+		{Severity: SeverityInfo, Script: "main.sh", Line: 5, Rule: RuleUnresolvedCommand, Message: "'helper.sh' solo está en el PATH cuando se ejecuta con gorunscript"},
+		{Severity: SeverityError, Script: "main.sh", Line: 7, Rule: RuleUnresolvedCommand, Message: "'noexiste.sh' no está en el catálogo ni en el PATH"},
+		{Severity: SeverityWarning, Script: "main.sh", Line: 8, Rule: RuleUnresolvedCommand, Message: "'opcional.sh' no está en el catálogo ni en el PATH"},
+		{Severity: SeverityWarning, Script: "main.sh", Line: 9, Rule: RuleUnresolvedCommand, Message: "'opcional.sh' no está en el catálogo ni en el PATH"},
+		{Severity: SeverityWarning, Script: "vacio.sh", Rule: RuleEmptyScript, Message: "el script no contiene ningún comando"},
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("Problemas inesperados:")
+		for _, issue := range issues {
+			t.Logf("  %s", issue)
+		}
+	}
+}
+
+// TestLintEmbeddedScripts comprueba que los scripts embebidos no tengan referencias rotas
+func TestLintEmbeddedScripts(t *testing.T) {
+	issues, err := NewBashRunner().Lint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emptyReported := false
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			t.Errorf("Error de lint: %s", issue)
+		}
+		if issue.Script == "goget-all.sh" && issue.Rule == RuleEmptyScript {
+			emptyReported = true
+		}
+	}
+	if !emptyReported {
+		t.Error("Se esperaba que goget-all.sh se informara como script vacío")
+	}
+}
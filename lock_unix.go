@@ -0,0 +1,35 @@
+//go:build !windows
+
+package gorunscript
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile obtiene un flock exclusivo sobre el archivo de bloqueo
+func lockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
+
+// releaseLock libera el flock y cierra el archivo
+func releaseLock(f *os.File) {
+	_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	_ = f.Close()
+}
+
+// lockIsStale indica si nadie mantiene el bloqueo; el sistema libera el flock de un
+// proceso al morir, por lo que un bloqueo obtenible pertenece a un proceso caído
+func lockIsStale(path string) bool {
+	f, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	defer f.Close()
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		return false
+	}
+	_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	return true
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package gorunscript
+
+import (
+	"os"
+)
+
+// lockFile no necesita hacer nada: mantener el archivo abierto basta, porque Windows no
+// permite eliminar un archivo abierto sin FILE_SHARE_DELETE y eso sirve de bloqueo entre procesos
+func lockFile(f *os.File) error {
+	return nil
+}
+
+// releaseLock cierra el archivo de bloqueo
+func releaseLock(f *os.File) {
+	_ = f.Close()
+}
+
+// lockIsStale intenta eliminar el archivo de bloqueo; solo es posible si su dueño ya no lo tiene abierto
+func lockIsStale(path string) bool {
+	err := os.Remove(path)
+	return err == nil || os.IsNotExist(err)
+}
@@ -0,0 +1,67 @@
+package gorunscript
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ExecOptions son las opciones de una invocación concreta. El valor cero reproduce el
+// comportamiento de ExecuteScript: espacio de trabajo aislado y entorno heredado.
+type ExecOptions struct {
+	// Dir es el directorio de trabajo del script, por ejemplo el repositorio sobre el que
+	// deben operar git o go. Vacío usa el espacio de trabajo aislado de la invocación.
+	// Los scripts auxiliares siguen resolviéndose porque su directorio se antepone al PATH.
+	Dir string
+
+	// Env son variables adicionales con formato "CLAVE=valor"; tienen prioridad sobre las heredadas
+	Env []string
+
+	// CleanEnv evita heredar el entorno del proceso. Solo se conservan PATH y las
+	// variables nombradas en EnvAllowlist.
+	CleanEnv     bool
+	EnvAllowlist []string
+
+	// Stdin es la entrada estándar del script; nil equivale a una entrada vacía
+	Stdin io.Reader
+
+	// Streams transmite stdout y stderr mientras el script se ejecuta
+	Streams StreamOptions
+
+	// OnEvent recibe, mientras el script se ejecuta, los eventos que envía con los
+	// auxiliares de events.sh. Las llamadas se hacen desde una única goroutine, en orden.
+	// No disponible en Windows.
+	OnEvent func(Event)
+
+	// PTY ejecuta el script en una pseudoterminal; nil usa tuberías. Solo en Linux.
+	PTY *PTYOptions
+
+	// Expect responde automáticamente a las preguntas del script; sustituye a Stdin
+	Expect *ExpectOptions
+
+	// extraPath son directorios antepuestos al PATH tras el de scripts, como los shims de DryRun
+	extraPath []string
+}
+
+// workDir valida y devuelve el directorio de trabajo absoluto solicitado, o vacío si no hay ninguno
+func (o ExecOptions) workDir() (string, error) {
+	if o.Dir == "" {
+		return "", nil
+	}
+
+	dir, err := filepath.Abs(o.Dir)
+	if err != nil {
+		return "", fmt.Errorf("error resolviendo directorio de trabajo %s: %w", o.Dir, err)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("error: directorio de trabajo no encontrado en %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("error: %s no es un directorio", dir)
+	}
+
+	return dir, nil
+}
@@ -0,0 +1,82 @@
+package gorunscript
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExecuteScriptWithOptions(t *testing.T) {
+	projectRoot := newTempProject(t, map[string]string{
+		"helpers.sh": "greet() { echo \"hola $1\"; }\n",
+		"where.sh":   "#!/bin/bash\nsource helpers.sh\ngreet desde-helpers\necho \"dir=$(pwd)\"\n",
+		"env.sh":     "#!/bin/bash\necho \"extra=$EXTRA_VAR\"\necho \"kept=$KEPT_VAR\"\necho \"leaked=$LEAKED_VAR\"\n",
+		"stdin.sh":   "#!/bin/bash\nwhile read -r line; do echo \"leído: $line\"; done\n",
+	})
+	runner := NewBashRunnerWithOptions(projectRoot)
+	runner.SetWorkspaceRoot(t.TempDir())
+	ctx := context.Background()
+
+	t.Run("Directorio de trabajo con helpers resueltos", func(t *testing.T) {
+		repoDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(repoDir, "go.mod"), []byte("module x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, output, err := runner.ExecuteScriptWithOptions(ctx, ExecOptions{Dir: repoDir}, "where")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, output)
+		}
+
+		if !strings.Contains(output, "hola desde-helpers") {
+			t.Errorf("No se resolvió `source helpers.sh`: %s", output)
+		}
+
+		realDir, _ := filepath.EvalSymlinks(repoDir)
+		if !strings.Contains(output, "dir="+repoDir) && !strings.Contains(output, "dir="+realDir) {
+			t.Errorf("El script no se ejecutó en %s: %s", repoDir, output)
+		}
+	})
+
+	t.Run("Directorio inexistente", func(t *testing.T) {
+		opts := ExecOptions{Dir: filepath.Join(t.TempDir(), "no-existe")}
+		if _, _, err := runner.ExecuteScriptWithOptions(ctx, opts, "where"); err == nil {
+			t.Error("Se esperaba un error por directorio inexistente")
+		}
+	})
+
+	t.Run("Variables adicionales y entorno limpio", func(t *testing.T) {
+		t.Setenv("KEPT_VAR", "conservada")
+		t.Setenv("LEAKED_VAR", "filtrada")
+
+		opts := ExecOptions{
+			Env:          []string{"EXTRA_VAR=añadida"},
+			CleanEnv:     true,
+			EnvAllowlist: []string{"KEPT_VAR"},
+		}
+		_, output, err := runner.ExecuteScriptWithOptions(ctx, opts, "env")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, output)
+		}
+
+		for _, want := range []string{"extra=añadida", "kept=conservada", "leaked=\n"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("La salida no contiene %q: %s", want, output)
+			}
+		}
+	})
+
+	t.Run("Entrada estándar", func(t *testing.T) {
+		opts := ExecOptions{Stdin: strings.NewReader("uno\ndos\n")}
+		_, output, err := runner.ExecuteScriptWithOptions(ctx, opts, "stdin")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, output)
+		}
+
+		if !strings.Contains(output, "leído: uno") || !strings.Contains(output, "leído: dos") {
+			t.Errorf("El script no recibió stdin: %s", output)
+		}
+	})
+}
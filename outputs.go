@@ -0,0 +1,235 @@
+package gorunscript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResultFileEnv es la variable de entorno con la ruta del archivo en el que el script
+// publica sus salidas con nombre. Los auxiliares de outputs.sh no hacen nada si no está definida.
+const ResultFileEnv = "GORUNSCRIPT_RESULT_FILE"
+
+// newResultFile crea el archivo de salidas vacío de un proceso dentro de dir
+func newResultFile(dir string) (string, error) {
+	f, err := os.CreateTemp(dir, "outputs-*")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	if err := f.Close(); err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// readResultFile lee y elimina el archivo de salidas
+func readResultFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	_ = os.Remove(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseOutputs(data)
+}
+
+// parseOutputs decodifica las salidas publicadas por un script. Si el contenido es un
+// objeto JSON, cada propiedad es una salida: las cadenas se conservan tal cual y el resto
+// de valores como texto JSON. Si no, cada línea es `clave=valor` o, para valores de varias
+// líneas, `clave<<DELIM` seguida del valor y de una línea con DELIM. Las líneas vacías y las
+// que empiezan por # se ignoran; si una clave se repite gana el último valor.
+func parseOutputs(data []byte) (map[string]string, error) {
+	outputs := make(map[string]string)
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return outputs, nil
+	}
+	if trimmed[0] == '{' {
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal(trimmed, &doc); err != nil {
+			return nil, fmt.Errorf("JSON no válido: %w", err)
+		}
+		for key, raw := range doc {
+			var s string
+			if err := json.Unmarshal(raw, &s); err == nil {
+				outputs[key] = s
+			} else {
+				outputs[key] = string(raw)
+			}
+		}
+		return outputs, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if key, delim, ok := strings.Cut(line, "<<"); ok && !strings.Contains(key, "=") {
+			start := lineNum
+			var value []string
+			closed := false
+			for scanner.Scan() {
+				lineNum++
+				next := strings.TrimRight(scanner.Text(), "\r")
+				if next == delim {
+					closed = true
+					break
+				}
+				value = append(value, next)
+			}
+			if !closed {
+				return nil, fmt.Errorf("línea %d: falta el delimitador %q de %s", start, delim, key)
+			}
+			outputs[strings.TrimSpace(key)] = strings.Join(value, "\n")
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("línea %d: se esperaba clave=valor: %q", lineNum, line)
+		}
+		outputs[strings.TrimSpace(key)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+// DecodeOutputs decodifica las salidas del resultado en un valor de tipo T, que debe ser
+// un struct. Cada campo exportado toma la salida indicada por su etiqueta `output`, o si
+// no tiene, por su etiqueta `json` o su nombre sin distinguir mayúsculas; `output:"-"` lo
+// omite. Los textos se convierten al tipo del campo: números, bool, time.Duration,
+// encoding.TextUnmarshaler y, para slices, maps y structs, texto JSON.
+//
+//	type versions struct {
+//		Local  string `output:"local_version"`
+//		Remote string `output:"remote_version"`
+//	}
+//	v, err := gorunscript.DecodeOutputs[versions](res)
+func DecodeOutputs[T any](res *Result) (T, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	if rv.Kind() != reflect.Struct {
+		return v, fmt.Errorf("%w: %T no es un struct", ErrOutputs, v)
+	}
+	if res == nil {
+		return v, nil
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		value, ok := lookupOutput(res.Outputs, field)
+		if !ok {
+			continue
+		}
+		if err := setOutputField(rv.Field(i), value); err != nil {
+			return v, fmt.Errorf("%w: %s: %w", ErrOutputs, field.Name, err)
+		}
+	}
+	return v, nil
+}
+
+// lookupOutput busca la salida que corresponde a field
+func lookupOutput(outputs map[string]string, field reflect.StructField) (string, bool) {
+	if name, ok := field.Tag.Lookup("output"); ok {
+		if name == "-" {
+			return "", false
+		}
+		value, found := outputs[name]
+		return value, found
+	}
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+		if value, found := outputs[name]; found {
+			return value, true
+		}
+	}
+	if value, found := outputs[field.Name]; found {
+		return value, true
+	}
+	for key, value := range outputs {
+		if strings.EqualFold(key, field.Name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// setOutputField convierte value al tipo de fv y lo asigna
+func setOutputField(fv reflect.Value, value string) error {
+	if fv.Kind() == reflect.Pointer {
+		ptr := reflect.New(fv.Type().Elem())
+		if err := setOutputField(ptr.Elem(), value); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+		return nil
+	}
+	if reflect.PointerTo(fv.Type()).Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	default:
+		return json.Unmarshal([]byte(value), fv.Addr().Interface())
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseOutputs(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{"Vacío", "\n", map[string]string{}},
+		{
+			"Clave y valor",
+			"# comentario\nversion=v0.0.2\nvacío=\nexpr=a=b\r\nversion=v0.0.3\n",
+			map[string]string{"version": "v0.0.3", "vacío": "", "expr": "a=b"},
+		},
+		{
+			"Varias líneas",
+			"notas<<FIN\nprimera\n\nsegunda\nFIN\nok=true\n",
+			map[string]string{"notas": "primera\n\nsegunda", "ok": "true"},
+		},
+		{
+			"JSON",
+			` {"version": "v0.0.2", "count": 3, "files": ["a.go", "b.go"], "ok": true}`,
+			map[string]string{"version": "v0.0.2", "count": "3", "files": `["a.go", "b.go"]`, "ok": "true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOutputs([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Error inesperado: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Salidas inesperadas:\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"sin separador\n", "notas<<FIN\nsin cerrar\n", "{roto"} {
+		if _, err := parseOutputs([]byte(input)); err == nil {
+			t.Errorf("Se esperaba error para %q", input)
+		}
+	}
+}
+
+type decodedOutputs struct {
+	Version  string `output:"version"`
+	Count    int    `json:"count"`
+	Ok       bool
+	Files    []string      `output:"files"`
+	Timeout  time.Duration `output:"timeout"`
+	Ratio    *float64      `output:"ratio"`
+	At       time.Time     `output:"at"`
+	Ignored  string        `output:"-"`
+	NotFound string
+}
+
+func TestDecodeOutputs(t *testing.T) {
+	res := &Result{Outputs: map[string]string{
+		"version": "v0.0.2",
+		"count":   "3",
+		"OK":      "true",
+		"files":   `["a.go","b.go"]`,
+		"timeout": "1m30s",
+		"ratio":   "0.5",
+		"at":      "2024-01-02T03:04:05Z",
+		"Ignored": "x",
+	}}
+
+	got, err := DecodeOutputs[decodedOutputs](res)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if got.Version != "v0.0.2" || got.Count != 3 || !got.Ok || got.Timeout != 90*time.Second {
+		t.Errorf("Decodificación inesperada: %+v", got)
+	}
+	if !reflect.DeepEqual(got.Files, []string{"a.go", "b.go"}) || got.Ratio == nil || *got.Ratio != 0.5 {
+		t.Errorf("Decodificación inesperada: %+v", got)
+	}
+	if !got.At.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) || got.Ignored != "" || got.NotFound != "" {
+		t.Errorf("Decodificación inesperada: %+v", got)
+	}
+
+	res.Outputs["count"] = "tres"
+	if _, err := DecodeOutputs[decodedOutputs](res); !errors.Is(err, ErrOutputs) {
+		t.Errorf("Se esperaba ErrOutputs, se obtuvo %v", err)
+	}
+	if _, err := DecodeOutputs[map[string]string](res); !errors.Is(err, ErrOutputs) {
+		t.Errorf("Se esperaba ErrOutputs para un tipo que no es struct, se obtuvo %v", err)
+	}
+}
+
+func TestScriptOutputs(t *testing.T) {
+	projectRoot := newTempProject(t, map[string]string{
+		"salidas.sh": "#!/bin/bash\nsource outputs.sh\n" +
+			"set_output version v0.0.2\nset_output notas $'primera\\nsegunda'\n" +
+			"echo salida normal\n",
+		"json.sh":     "#!/bin/bash\nsource outputs.sh\nset_output previa x\nset_outputs_json '{\"count\": 2, \"files\": [\"a.go\"]}'\n",
+		"invalido.sh": "#!/bin/bash\necho 'sin separador' > \"$GORUNSCRIPT_RESULT_FILE\"\n",
+		"ninguna.sh":  "#!/bin/bash\nexit 0\n",
+	})
+	runner := NewBashRunnerWithOptions(projectRoot)
+	runner.SetWorkspaceRoot(t.TempDir())
+	ctx := context.Background()
+
+	res, err := runner.Run(ctx, ExecOptions{}, "salidas")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+	}
+	want := map[string]string{"version": "v0.0.2", "notas": "primera\nsegunda"}
+	if !reflect.DeepEqual(res.Outputs, want) {
+		t.Errorf("Salidas inesperadas: %q", res.Outputs)
+	}
+	if res.Stdout != "salida normal\n" {
+		t.Errorf("Las salidas no deben mezclarse con stdout: %q", res.Stdout)
+	}
+
+	res, err = runner.Run(ctx, ExecOptions{}, "json")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+	}
+	decoded, err := DecodeOutputs[struct {
+		Count int
+		Files []string
+	}](res)
+	if err != nil || decoded.Count != 2 || !reflect.DeepEqual(decoded.Files, []string{"a.go"}) {
+		t.Errorf("Decodificación inesperada: %+v, %v", decoded, err)
+	}
+	if _, ok := res.Outputs["previa"]; ok {
+		t.Errorf("set_outputs_json debe reemplazar las salidas anteriores: %q", res.Outputs)
+	}
+
+	res, err = runner.Run(ctx, ExecOptions{}, "invalido")
+	if !errors.Is(err, ErrOutputs) {
+		t.Errorf("Se esperaba ErrOutputs, se obtuvo %v", err)
+	}
+	if !res.Success() {
+		t.Errorf("El script terminó correctamente: %+v", res)
+	}
+
+	res, err = runner.Run(ctx, ExecOptions{}, "ninguna")
+	if err != nil || len(res.Outputs) != 0 {
+		t.Errorf("No se esperaban salidas: %q, %v", res.Outputs, err)
+	}
+}
@@ -0,0 +1,206 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Pipeline conecta varios scripts como una tubería de shell: la salida estándar de cada
+// etapa es la entrada estándar de la siguiente. Las etapas se ejecutan a la vez sobre una
+// tubería del sistema, por lo que una etapa lenta frena a la anterior (contrapresión).
+type Pipeline struct {
+	runner   *ScriptRunner
+	stages   []Job
+	pipefail bool
+}
+
+// PipelineResult describe una ejecución de la tubería
+type PipelineResult struct {
+	// Stages contiene el resultado de cada etapa; el Stdout de las etapas intermedias está
+	// vacío porque se entregó a la siguiente
+	Stages []*Result
+
+	// PipeStatus contiene el código de salida de cada etapa, como PIPESTATUS en bash
+	PipeStatus []int
+
+	// Stdout es la salida estándar de la última etapa
+	Stdout string
+}
+
+// Pipeline crea una tubería vacía sobre el runner
+func (sr *ScriptRunner) Pipeline() *Pipeline {
+	return &Pipeline{runner: sr}
+}
+
+// Pipe añade una etapa al final de la tubería
+func (p *Pipeline) Pipe(scriptName string, args ...string) *Pipeline {
+	p.stages = append(p.stages, Job{Script: scriptName, Args: args})
+	return p
+}
+
+// SetPipefail hace que la tubería falle si falla cualquier etapa, como `set -o pipefail`.
+// Por defecto solo cuenta la última etapa.
+func (p *Pipeline) SetPipefail(enabled bool) *Pipeline {
+	p.pipefail = enabled
+	return p
+}
+
+// Run ejecuta la tubería. opts.Stdin alimenta la primera etapa y opts.Streams.Stdout recibe
+// la salida de la última; el stderr de todas las etapas se transmite a opts.Streams.Stderr.
+// Si el contexto se cancela o una etapa no puede iniciarse, se terminan todas las etapas.
+// El error es el de la última etapa, o con pipefail el de la etapa fallida más a la derecha.
+func (p *Pipeline) Run(ctx context.Context, opts ExecOptions) (*PipelineResult, error) {
+	sr := p.runner
+	result := &PipelineResult{}
+	if len(p.stages) == 0 {
+		return result, errors.New("error: la tubería no tiene etapas")
+	}
+	if opts.PTY != nil || opts.Expect != nil {
+		return result, errors.New("error: las tuberías no admiten el modo PTY ni Expect")
+	}
+
+	for _, stage := range p.stages {
+		result.Stages = append(result.Stages, sr.newResult(stage.Script, stage.Args))
+	}
+	result.PipeStatus = make([]int, len(p.stages))
+	for i := range result.PipeStatus {
+		result.PipeStatus[i] = 1
+	}
+
+	inv, err := sr.prepare(ctx, opts)
+	if err != nil {
+		return result, err
+	}
+	defer inv.release()
+
+	// Resolver todas las etapas antes de iniciar ninguna
+	scripts := make([]scriptFile, len(p.stages))
+	for i, stage := range p.stages {
+		script, ok := findScript(inv.files, stage.Script, sr.interpreters)
+		if !ok {
+			return result, newScriptNotFoundError(script.name, inv.files)
+		}
+		scripts[i] = script
+	}
+
+	if err := sr.extract(ctx, inv, scripts...); err != nil {
+		return result, err
+	}
+	for i, script := range scripts {
+		res := result.Stages[i]
+		res.Script = script.name
+		res.ScriptPath = filepath.Join(inv.scriptsDir, script.name)
+		res.Layer = script.layer
+	}
+
+	// Una cancelación común termina todas las etapas juntas
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stageStreams := sharedStreams(opts.Streams)
+	procs := make([]*process, len(p.stages))
+	stdin := opts.Stdin
+
+	for i := range p.stages {
+		stageOpts := opts
+		stageOpts.Stdin = stdin
+		stageOpts.Streams = StreamOptions{Stderr: stageStreams.Stderr, OnLine: stageStreams.OnLine}
+
+		var pr, pw *os.File
+		last := i == len(p.stages)-1
+		if last {
+			stageOpts.Streams.Stdout = stageStreams.Stdout
+		} else {
+			pr, pw, err = os.Pipe()
+			if err != nil {
+				if f, ok := stdin.(*os.File); ok && i > 0 {
+					_ = f.Close()
+				}
+				cancel()
+				p.waitAll(procs)
+				return result, fmt.Errorf("error creando tubería: %w", err)
+			}
+		}
+
+		procs[i], err = sr.start(ctx, inv, stageOpts, sr.interpreterFor(scripts[i]), result.Stages[i], pw)
+
+		// El proceso padre no debe conservar sus copias de los extremos ya entregados, o
+		// las etapas nunca verían EOF ni SIGPIPE
+		if pw != nil {
+			_ = pw.Close()
+		}
+		if f, ok := stdin.(*os.File); ok && i > 0 {
+			_ = f.Close()
+		}
+
+		if err != nil {
+			if pr != nil {
+				_ = pr.Close()
+			}
+			cancel()
+			p.waitAll(procs)
+			return result, err
+		}
+		stdin = pr
+	}
+
+	errs := p.waitAll(procs)
+	for i, res := range result.Stages {
+		result.PipeStatus[i] = res.ExitCode
+	}
+	result.Stdout = result.Stages[len(result.Stages)-1].Stdout
+
+	if !p.pipefail {
+		return result, errs[len(errs)-1]
+	}
+	for i := len(errs) - 1; i >= 0; i-- {
+		if errs[i] != nil {
+			return result, errs[i]
+		}
+	}
+	return result, nil
+}
+
+// waitAll espera a todas las etapas iniciadas y devuelve el error de cada una
+func (p *Pipeline) waitAll(procs []*process) []error {
+	errs := make([]error, len(procs))
+	var wg sync.WaitGroup
+	for i, proc := range procs {
+		if proc == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, proc *process) {
+			defer wg.Done()
+			errs[i] = proc.wait()
+		}(i, proc)
+	}
+	wg.Wait()
+	return errs
+}
+
+// sharedStreams adapta los destinos del usuario para que varias etapas escriban en ellos
+// a la vez sin intercalar escrituras ni invocar OnLine en paralelo
+func sharedStreams(streams StreamOptions) StreamOptions {
+	var mu sync.Mutex
+	shared := StreamOptions{}
+	if streams.Stdout != nil {
+		shared.Stdout = &lockedWriter{mu: &mu, w: streams.Stdout}
+	}
+	if streams.Stderr != nil {
+		shared.Stderr = &lockedWriter{mu: &mu, w: streams.Stderr}
+	}
+	if streams.OnLine != nil {
+		var lineMu sync.Mutex
+		shared.OnLine = func(stream StreamKind, line string) {
+			lineMu.Lock()
+			defer lineMu.Unlock()
+			streams.OnLine(stream, line)
+		}
+	}
+	return shared
+}
@@ -0,0 +1,105 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	projectRoot := newTempProject(t, map[string]string{
+		"gen.sh":     "#!/bin/bash\nfor i in $(seq 1 \"$1\"); do echo \"linea $i\"; done\nexit \"${2:-0}\"\n",
+		"odd.sh":     "#!/bin/bash\nwhile read -r w n; do [ $((n % 2)) -eq 1 ] && echo \"$w $n\"; done\nexit 0\n",
+		"count.sh":   "#!/bin/bash\necho \"contadas: $(wc -l | tr -d ' ')\" \necho listo >&2\n",
+		"endless.sh": "#!/bin/bash\nyes\n",
+		"first.sh":   "#!/bin/bash\nhead -n 3\n",
+		"slow.sh":    "#!/bin/bash\nsleep 30\n",
+		"cat.sh":     "#!/bin/bash\ncat\n",
+	})
+	runner := NewBashRunnerWithOptions(projectRoot)
+	runner.SetWorkspaceRoot(t.TempDir())
+	runner.SetKillGracePeriod(200 * time.Millisecond)
+	ctx := context.Background()
+
+	t.Run("Tres etapas conectadas", func(t *testing.T) {
+		res, err := runner.Pipeline().Pipe("gen", "5").Pipe("odd").Pipe("count").Run(ctx, ExecOptions{})
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+
+		if strings.TrimSpace(res.Stdout) != "contadas: 3" {
+			t.Errorf("Salida inesperada: %q", res.Stdout)
+		}
+		if !reflect.DeepEqual(res.PipeStatus, []int{0, 0, 0}) {
+			t.Errorf("PipeStatus inesperado: %v", res.PipeStatus)
+		}
+		if res.Stages[0].Stdout != "" || res.Stages[2].Stderr != "listo\n" {
+			t.Errorf("Captura por etapa inesperada: %q / %q", res.Stages[0].Stdout, res.Stages[2].Stderr)
+		}
+	})
+
+	t.Run("Entrada de la primera etapa", func(t *testing.T) {
+		res, err := runner.Pipeline().Pipe("odd").Pipe("count").Run(ctx, ExecOptions{Stdin: strings.NewReader("a 1\nb 2\nc 3\n")})
+		if err != nil || strings.TrimSpace(res.Stdout) != "contadas: 2" {
+			t.Errorf("Salida inesperada %q: %v", res.Stdout, err)
+		}
+	})
+
+	t.Run("PipeStatus y pipefail", func(t *testing.T) {
+		res, err := runner.Pipeline().Pipe("gen", "2", "3").Pipe("count").Run(ctx, ExecOptions{})
+		if err != nil {
+			t.Errorf("Sin pipefail solo cuenta la última etapa: %v", err)
+		}
+		if !reflect.DeepEqual(res.PipeStatus, []int{3, 0}) {
+			t.Errorf("PipeStatus inesperado: %v", res.PipeStatus)
+		}
+
+		_, err = runner.Pipeline().Pipe("gen", "2", "3").Pipe("count").SetPipefail(true).Run(ctx, ExecOptions{})
+		var scriptErr *ScriptError
+		if !errors.As(err, &scriptErr) || scriptErr.Script != "gen.sh" || scriptErr.ExitCode != 3 {
+			t.Errorf("Se esperaba el error de gen.sh con pipefail, se obtuvo: %v", err)
+		}
+	})
+
+	t.Run("Contrapresión y cierre de la tubería", func(t *testing.T) {
+		done := make(chan struct{})
+		var res *PipelineResult
+		go func() {
+			defer close(done)
+			res, _ = runner.Pipeline().Pipe("endless").Pipe("first").Run(ctx, ExecOptions{})
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("La etapa productora no terminó al cerrarse la consumidora")
+		}
+		if res.Stdout != "y\ny\ny\n" {
+			t.Errorf("Salida inesperada: %q", res.Stdout)
+		}
+	})
+
+	t.Run("Cancelación de todas las etapas", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := runner.Pipeline().Pipe("slow").Pipe("cat").Run(ctx, ExecOptions{})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Se esperaba un timeout, se obtuvo: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("La cancelación tardó demasiado: %v", elapsed)
+		}
+	})
+
+	t.Run("Etapa inexistente", func(t *testing.T) {
+		_, err := runner.Pipeline().Pipe("gen", "1").Pipe("no-existe").Run(ctx, ExecOptions{})
+		if !errors.Is(err, ErrScriptNotFound) {
+			t.Errorf("Se esperaba ErrScriptNotFound, se obtuvo: %v", err)
+		}
+	})
+}
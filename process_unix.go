@@ -0,0 +1,64 @@
+//go:build !windows
+
+package gorunscript
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// eventFDSupported indica si el proceso hijo puede heredar el descriptor de eventos
+const eventFDSupported = true
+
+// configureProcessGroup inicia el comando en un grupo de procesos propio y define la
+// cancelación: SIGTERM a todo el grupo y SIGKILL una vez transcurrido el periodo de gracia.
+// Devuelve una función que debe llamarse tras Wait para rematar procesos rezagados.
+func configureProcessGroup(cmd *exec.Cmd, grace time.Duration) (stop func()) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+
+	var (
+		mu        sync.Mutex
+		timer     *time.Timer
+		cancelled bool
+	)
+
+	cmd.Cancel = func() error {
+		pgid := cmd.Process.Pid
+		err := syscall.Kill(-pgid, syscall.SIGTERM)
+
+		mu.Lock()
+		cancelled = true
+		timer = time.AfterFunc(grace, func() {
+			_ = syscall.Kill(-pgid, syscall.SIGKILL)
+		})
+		mu.Unlock()
+		return err
+	}
+	// Si algún nieto mantiene abiertas las tuberías, Wait no espera indefinidamente
+	cmd.WaitDelay = grace + time.Second
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if !cancelled {
+			return
+		}
+		timer.Stop()
+		// Cualquier proceso que siga vivo en el grupo tras cancelar se termina de inmediato
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// exitSignal devuelve la señal que terminó el proceso, o nil si salió normalmente
+func exitSignal(state *os.ProcessState) os.Signal {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return status.Signal()
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+//go:build !windows
+
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestExecuteScriptContext(t *testing.T) {
+	// Proyecto temporal con un script que deja un nieto colgado
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	projectRoot := newTempProject(t, map[string]string{
+		"hang.sh": "#!/bin/bash\nsleep 30 &\necho $! > " + pidFile + "\nwait\n",
+	})
+
+	runner := NewBashRunnerWithOptions(projectRoot)
+	runner.SetWorkspaceRoot(t.TempDir())
+	runner.SetKillGracePeriod(200 * time.Millisecond)
+
+	t.Run("Timeout termina el árbol de procesos", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, _, err := runner.ExecuteScriptContext(ctx, "hang")
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("La cancelación tardó demasiado: %v", elapsed)
+		}
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Se esperaba un error de timeout, se obtuvo: %v", err)
+		}
+
+		var scriptErr *ScriptError
+		if !errors.As(err, &scriptErr) || !scriptErr.Timeout() {
+			t.Errorf("Se esperaba un *ScriptError con Timeout(), se obtuvo: %v", err)
+		}
+
+		data, err := os.ReadFile(pidFile)
+		if err != nil {
+			t.Fatalf("No se pudo leer el pid del proceso nieto: %v", err)
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// El nieto debe haber terminado junto con el grupo
+		deadline := time.Now().Add(2 * time.Second)
+		for syscall.Kill(pid, 0) == nil {
+			if time.Now().After(deadline) {
+				t.Fatalf("El proceso nieto %d sigue vivo tras la cancelación", pid)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	})
+
+	t.Run("Contexto ya cancelado", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, _, err := runner.ExecuteScriptContext(ctx, "hang")
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Se esperaba context.Canceled, se obtuvo: %v", err)
+		}
+	})
+}
+
+func TestRunResultSignal(t *testing.T) {
+	projectRoot := newTempProject(t, map[string]string{
+		"killed.sh": "#!/bin/bash\nkill -KILL $$\n",
+	})
+	runner := NewBashRunnerWithOptions(projectRoot)
+	runner.SetWorkspaceRoot(t.TempDir())
+
+	res, err := runner.Run(context.Background(), ExecOptions{}, "killed")
+	if err == nil {
+		t.Fatal("Se esperaba un error al terminar por señal")
+	}
+	if res.Signal != syscall.SIGKILL || res.ExitCode != -1 {
+		t.Errorf("Se esperaba SIGKILL y código -1, se obtuvo %v y %d", res.Signal, res.ExitCode)
+	}
+}
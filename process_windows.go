@@ -0,0 +1,33 @@
+//go:build windows
+
+package gorunscript
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// eventFDSupported es false porque Windows no permite heredar descriptores adicionales
+const eventFDSupported = false
+
+// configureProcessGroup en Windows termina el árbol completo de procesos con taskkill,
+// ya que no existen grupos de procesos POSIX ni señales SIGTERM.
+func configureProcessGroup(cmd *exec.Cmd, grace time.Duration) (stop func()) {
+	cmd.Cancel = func() error {
+		pid := strconv.Itoa(cmd.Process.Pid)
+		if err := exec.Command("taskkill", "/T", "/F", "/PID", pid).Run(); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = grace + time.Second
+
+	return func() {}
+}
+
+// exitSignal siempre devuelve nil: en Windows los procesos no terminan por señales
+func exitSignal(state *os.ProcessState) os.Signal {
+	return nil
+}
@@ -0,0 +1,270 @@
+package gorunscript
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tamaño de la terminal cuando PTYOptions no indica ninguno
+const (
+	defaultPTYRows = 24
+	defaultPTYCols = 80
+)
+
+// ptyDrainTimeout es cuánto se sigue leyendo la terminal, tras terminar el script, si algún
+// proceso en segundo plano la mantiene abierta
+const ptyDrainTimeout = 500 * time.Millisecond
+
+// ptyInputPoll es cada cuánto se comprueba, mientras se espera entrada, si el proceso
+// terminó, para dejar de leer sin quedarse con datos destinados al llamador
+const ptyInputPoll = 100 * time.Millisecond
+
+// ptyTerm es el valor de TERM en modo PTY si el entorno no define ninguno
+const ptyTerm = "xterm-256color"
+
+// WindowSize es el tamaño de una terminal en caracteres
+type WindowSize struct {
+	Rows uint16
+	Cols uint16
+}
+
+// PTYOptions ejecuta el script conectado a una pseudoterminal en lugar de a tuberías, de
+// modo que `read -p`, las barras de progreso y los colores se comportan como en una
+// terminal real. stdout y stderr llegan mezclados por la terminal: Result.Stdout y
+// Result.Output contienen lo mismo, con los códigos de color intactos, y Result.Stderr
+// queda vacío. La entrada es ExecOptions.Stdin; al agotarse se envía fin de archivo
+// (Ctrl-D). Si Stdin es un *os.File, como os.Stdin, se deja de leer al terminar el
+// script y lo que se escriba después no se pierde. Solo disponible en Linux; en otros
+// sistemas se devuelve ErrPTYUnsupported.
+type PTYOptions struct {
+	// Size es el tamaño inicial; el valor cero equivale a 24x80
+	Size WindowSize
+
+	// Resize cambia el tamaño de la terminal mientras el script se ejecuta, p. ej. al
+	// recibir SIGWINCH en la terminal del llamador
+	Resize <-chan WindowSize
+
+	// Raw pone ExecOptions.Stdin en modo crudo durante la ejecución si es una terminal, para
+	// que cada tecla llegue al script sin esperar a Enter y sin eco duplicado. Junto con
+	// Streams.Stdout = os.Stdout permite ejecuciones totalmente interactivas.
+	Raw bool
+}
+
+// size devuelve el tamaño inicial de la terminal
+func (o *PTYOptions) size() WindowSize {
+	size := o.Size
+	if size.Rows == 0 {
+		size.Rows = defaultPTYRows
+	}
+	if size.Cols == 0 {
+		size.Cols = defaultPTYCols
+	}
+	return size
+}
+
+// ptySession conecta un proceso a una pseudoterminal y copia su salida a la captura
+type ptySession struct {
+	master, slave *os.File
+	opts          *PTYOptions
+	stdin         io.Reader
+	output        io.Writer
+	restore       func() // Restaura la terminal del llamador si se puso en modo crudo
+	expect        bool   // La entrada la escribe Expect
+
+	done      chan struct{} // Se cierra al terminar de leer la salida
+	inputDone chan struct{} // Se cierra al dejar de leer la entrada; nil si no se lee
+	stop      chan struct{} // Se cierra al terminar el proceso
+	stopped   sync.Once
+}
+
+// attachPTY abre una pseudoterminal y la convierte en la entrada, salida y terminal de
+// control de cmd. La salida se escribe en output.
+func attachPTY(cmd *exec.Cmd, opts ExecOptions, output io.Writer) (*ptySession, error) {
+	master, slave, err := openPTY()
+	if err != nil {
+		return nil, err
+	}
+	if err := setWindowSize(master, opts.PTY.size()); err != nil {
+		_ = master.Close()
+		_ = slave.Close()
+		return nil, err
+	}
+
+	cmd.Stdin = slave
+	cmd.Stdout = slave
+	cmd.Stderr = slave
+	configurePTY(cmd)
+	if !hasEnv(cmd.Env, "TERM") {
+		cmd.Env = append(cmd.Env, "TERM="+ptyTerm)
+	}
+
+	return &ptySession{
+		master: master,
+		slave:  slave,
+		opts:   opts.PTY,
+		stdin:  opts.Stdin,
+		output: output,
+		expect: opts.Expect != nil,
+		done:   make(chan struct{}),
+		stop:   make(chan struct{}),
+	}, nil
+}
+
+// start empieza a copiar la entrada y la salida; debe llamarse una vez iniciado el proceso
+func (s *ptySession) start() {
+	// La terminal esclava solo debe quedar abierta en el proceso hijo
+	_ = s.slave.Close()
+
+	if s.opts.Raw {
+		if f, ok := s.stdin.(*os.File); ok {
+			s.restore, _ = makeRaw(f)
+		}
+	}
+
+	go func() {
+		defer close(s.done)
+		// Al cerrarse la última copia de la esclava la lectura falla con EIO, que aquí
+		// equivale a fin de archivo
+		_, _ = io.Copy(s.output, s.master)
+	}()
+
+	// Con Expect la entrada son solo sus respuestas, sin fin de archivo
+	if !s.expect {
+		s.inputDone = make(chan struct{})
+		go s.copyInput()
+	}
+
+	if s.opts.Resize != nil {
+		go func() {
+			for {
+				select {
+				case size, ok := <-s.opts.Resize:
+					if !ok {
+						return
+					}
+					_ = setWindowSize(s.master, size)
+				case <-s.stop:
+					return
+				}
+			}
+		}()
+	}
+}
+
+// copyInput envía la entrada al script y, al agotarse, el carácter de fin de archivo. Si
+// la última línea no terminó en salto de línea hace falta un Ctrl-D para entregarla y otro
+// para el fin de archivo. Si la entrada es un *os.File deja de leer en cuanto termina el
+// proceso, de modo que lo que llegue después sigue disponible para el llamador.
+func (s *ptySession) copyInput() {
+	defer close(s.inputDone)
+
+	eof := []byte{4}
+	if s.stdin != nil {
+		var last byte = '\n'
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := s.readInput(buf)
+			if errors.Is(err, errPTYStopped) {
+				return
+			}
+			if n > 0 {
+				if _, werr := s.master.Write(buf[:n]); werr != nil {
+					return
+				}
+				last = buf[n-1]
+			}
+			if err != nil {
+				break
+			}
+		}
+		if last != '\n' {
+			eof = []byte{4, 4}
+		}
+	}
+
+	select {
+	case <-s.stop:
+	default:
+		_, _ = s.master.Write(eof)
+	}
+}
+
+// errPTYStopped indica que se dejó de leer la entrada porque el proceso terminó
+var errPTYStopped = errors.New("proceso terminado")
+
+// readInput lee de la entrada del script. Con un *os.File solo lee cuando hay datos,
+// comprobando entre esperas si el proceso terminó; otros io.Reader se leen directamente y
+// su lectura pendiente no puede interrumpirse.
+func (s *ptySession) readInput(buf []byte) (int, error) {
+	f, ok := s.stdin.(*os.File)
+	if !ok {
+		return s.stdin.Read(buf)
+	}
+
+	for {
+		select {
+		case <-s.stop:
+			return 0, errPTYStopped
+		default:
+		}
+
+		ready, err := waitReadable(f, ptyInputPoll)
+		if err != nil {
+			// Sin poder esperar, se lee como cualquier otro io.Reader
+			return f.Read(buf)
+		}
+		if !ready {
+			continue
+		}
+
+		select {
+		case <-s.stop:
+			return 0, errPTYStopped
+		default:
+			return f.Read(buf)
+		}
+	}
+}
+
+// abort libera la terminal cuando el proceso no llegó a iniciarse
+func (s *ptySession) abort() {
+	_ = s.slave.Close()
+	_ = s.master.Close()
+}
+
+// finish espera a leer toda la salida y libera la terminal. Si algún proceso en segundo
+// plano la mantiene abierta, se deja de leer tras ptyDrainTimeout.
+func (s *ptySession) finish() {
+	s.stopped.Do(func() { close(s.stop) })
+
+	select {
+	case <-s.done:
+	case <-time.After(ptyDrainTimeout):
+	}
+	_ = s.master.Close()
+	<-s.done
+
+	// Solo se espera a la entrada si su lectura puede interrumpirse
+	if _, ok := s.stdin.(*os.File); ok && s.inputDone != nil {
+		<-s.inputDone
+	}
+
+	if s.restore != nil {
+		s.restore()
+	}
+}
+
+// hasEnv indica si env define la variable key
+func hasEnv(env []string, key string) bool {
+	for _, kv := range env {
+		if k, _, _ := strings.Cut(kv, "="); envKey(k) == envKey(key) {
+			return true
+		}
+	}
+	return false
+}
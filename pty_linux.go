@@ -0,0 +1,153 @@
+//go:build linux
+
+package gorunscript
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// openPTY abre una pseudoterminal nueva y devuelve sus extremos maestro y esclavo
+func openPTY() (master, slave *os.File, err error) {
+	master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error abriendo pseudoterminal: %w", err)
+	}
+
+	// Desbloquear la esclava y obtener su número, como unlockpt y ptsname
+	var unlock int32
+	if err := ioctl(master, syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)); err != nil {
+		_ = master.Close()
+		return nil, nil, fmt.Errorf("error desbloqueando pseudoterminal: %w", err)
+	}
+	var n uint32
+	if err := ioctl(master, syscall.TIOCGPTN, unsafe.Pointer(&n)); err != nil {
+		_ = master.Close()
+		return nil, nil, fmt.Errorf("error obteniendo pseudoterminal: %w", err)
+	}
+
+	slave, err = os.OpenFile("/dev/pts/"+strconv.FormatUint(uint64(n), 10), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, fmt.Errorf("error abriendo pseudoterminal: %w", err)
+	}
+	return master, slave, nil
+}
+
+// winsize es la estructura de TIOCSWINSZ
+type winsize struct {
+	rows, cols, xpixel, ypixel uint16
+}
+
+// setWindowSize cambia el tamaño de la terminal; el proceso recibe SIGWINCH
+func setWindowSize(f *os.File, size WindowSize) error {
+	ws := winsize{rows: size.Rows, cols: size.Cols}
+	if err := ioctl(f, syscall.TIOCSWINSZ, unsafe.Pointer(&ws)); err != nil {
+		return fmt.Errorf("error cambiando tamaño de la terminal: %w", err)
+	}
+	return nil
+}
+
+// TerminalSize devuelve el tamaño de la terminal f, p. ej. os.Stdin, para usarlo en
+// PTYOptions.Size o enviarlo por PTYOptions.Resize
+func TerminalSize(f *os.File) (WindowSize, error) {
+	var ws winsize
+	if err := ioctl(f, syscall.TIOCGWINSZ, unsafe.Pointer(&ws)); err != nil {
+		return WindowSize{}, fmt.Errorf("error obteniendo tamaño de la terminal: %w", err)
+	}
+	return WindowSize{Rows: ws.rows, Cols: ws.cols}, nil
+}
+
+// configurePTY inicia el proceso en una sesión nueva con la terminal de su stdin como
+// terminal de control. La sesión también es su grupo de procesos, así que la cancelación
+// de configureProcessGroup sigue alcanzando a todos sus descendientes.
+func configurePTY(cmd *exec.Cmd) {
+	cmd.SysProcAttr.Setpgid = false
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0
+}
+
+// makeRaw pone la terminal f en modo crudo y devuelve la función que la restaura. Si f
+// no es una terminal devuelve un error y no cambia nada.
+func makeRaw(f *os.File) (func(), error) {
+	var old syscall.Termios
+	if err := ioctl(f, syscall.TCGETS, unsafe.Pointer(&old)); err != nil {
+		return nil, err
+	}
+
+	// Los mismos cambios que cfmakeraw
+	raw := old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Oflag &^= syscall.OPOST
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := ioctl(f, syscall.TCSETS, unsafe.Pointer(&raw)); err != nil {
+		return nil, err
+	}
+
+	return func() { _ = ioctl(f, syscall.TCSETS, unsafe.Pointer(&old)) }, nil
+}
+
+// pollIn es POLLIN de poll(2): hay datos que leer
+const pollIn = 0x1
+
+// pollFd es la estructura pollfd de poll(2)
+type pollFd struct {
+	fd      int32
+	events  int16
+	revents int16
+}
+
+// waitReadable espera como mucho timeout a que f tenga datos que leer, o esté cerrado por
+// el otro extremo, sin consumir nada
+func waitReadable(f *os.File, timeout time.Duration) (bool, error) {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return false, err
+	}
+
+	var n uintptr
+	var errno syscall.Errno
+	if err := conn.Control(func(fd uintptr) {
+		fds := pollFd{fd: int32(fd), events: pollIn}
+		ts := syscall.NsecToTimespec(int64(timeout))
+		n, _, errno = syscall.Syscall6(syscall.SYS_PPOLL, uintptr(unsafe.Pointer(&fds)), 1, uintptr(unsafe.Pointer(&ts)), 0, 0, 0)
+	}); err != nil {
+		return false, err
+	}
+	if errno == syscall.EINTR {
+		return false, nil
+	}
+	if errno != 0 {
+		return false, errno
+	}
+	return n > 0, nil
+}
+
+// ioctl ejecuta la operación req sobre f
+func ioctl(f *os.File, req uint, arg unsafe.Pointer) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var errno syscall.Errno
+	if err := conn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(req), uintptr(arg))
+	}); err != nil {
+		return err
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+//go:build linux
+
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func newPTYRunner(t *testing.T, scripts map[string]string) *ScriptRunner {
+	t.Helper()
+	runner := NewBashRunnerWithOptions(newTempProject(t, scripts))
+	runner.SetWorkspaceRoot(t.TempDir())
+	return runner
+}
+
+func TestPTYMode(t *testing.T) {
+	runner := newPTYRunner(t, map[string]string{
+		"terminal.sh": "#!/bin/bash\nsource functions.sh\n" +
+			"[ -t 0 ] && [ -t 1 ] && [ -t 2 ] && echo 'es una terminal'\n" +
+			"stty size\necho \"TERM=$TERM\"\necho 'a stderr' >&2\nsuccess 'en verde'\n",
+		"pregunta.sh": "#!/bin/bash\nread -p 'Continue anyway? (y/N) ' answer\necho \"respuesta: $answer\"\n",
+		"eof.sh":      "#!/bin/bash\nread line || echo \"fin de archivo tras '$line'\"\n",
+	})
+	ctx := context.Background()
+
+	t.Run("Terminal, tamaño y colores", func(t *testing.T) {
+		opts := ExecOptions{PTY: &PTYOptions{Size: WindowSize{Rows: 30, Cols: 100}}, Env: []string{"TERM=vt100"}}
+		res, err := runner.Run(ctx, opts, "terminal")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+
+		for _, want := range []string{"es una terminal\r\n", "30 100\r\n", "TERM=vt100\r\n", "a stderr\r\n", "\x1b[0;32men verde\x1b[0m"} {
+			if !strings.Contains(res.Output, want) {
+				t.Errorf("Se esperaba %q en la salida:\n%q", want, res.Output)
+			}
+		}
+		if res.Stdout != res.Output || res.Stderr != "" {
+			t.Errorf("En modo PTY toda la salida va a Stdout: %q, %q", res.Stdout, res.Stderr)
+		}
+		if got := res.Report.Successes(); len(got) != 1 || got[0] != "en verde" {
+			t.Errorf("Informe inesperado: %q", got)
+		}
+	})
+
+	t.Run("TERM por defecto", func(t *testing.T) {
+		res, err := runner.Run(ctx, ExecOptions{PTY: &PTYOptions{}, CleanEnv: true}, "terminal")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+		if !strings.Contains(res.Output, "24 80\r\n") || !strings.Contains(res.Output, "TERM="+ptyTerm) {
+			t.Errorf("Se esperaba el tamaño y TERM por defecto:\n%q", res.Output)
+		}
+	})
+
+	t.Run("read -p", func(t *testing.T) {
+		opts := ExecOptions{PTY: &PTYOptions{}, Stdin: strings.NewReader("y\n")}
+		res, err := runner.Run(ctx, opts, "pregunta")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+		if !strings.Contains(res.Output, "Continue anyway? (y/N) ") || !strings.Contains(res.Output, "respuesta: y\r\n") {
+			t.Errorf("Salida inesperada: %q", res.Output)
+		}
+	})
+
+	t.Run("Fin de archivo al agotarse la entrada", func(t *testing.T) {
+		for _, stdin := range []string{"", "parcial"} {
+			opts := ExecOptions{PTY: &PTYOptions{}, Stdin: strings.NewReader(stdin)}
+			res, err := runner.Run(ctx, opts, "eof")
+			if err != nil {
+				t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+			}
+			if want := "fin de archivo tras '" + stdin + "'"; !strings.Contains(res.Output, want) {
+				t.Errorf("Se esperaba %q en la salida: %q", want, res.Output)
+			}
+		}
+	})
+}
+
+func TestPTYResize(t *testing.T) {
+	runner := newPTYRunner(t, map[string]string{
+		"resize.sh": "#!/bin/bash\ntrap 'stty size; exit 0' WINCH\necho listo\n" +
+			"for i in $(seq 100); do sleep 0.05; done\nexit 1\n",
+	})
+
+	resize := make(chan WindowSize, 1)
+	opts := ExecOptions{
+		PTY: &PTYOptions{Resize: resize},
+		Streams: StreamOptions{OnLine: func(stream StreamKind, line string) {
+			if line == "listo" {
+				resize <- WindowSize{Rows: 40, Cols: 120}
+			}
+		}},
+	}
+
+	res, err := runner.Run(context.Background(), opts, "resize")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+	}
+	if !strings.Contains(res.Output, "40 120\r\n") {
+		t.Errorf("Se esperaba el nuevo tamaño en la salida: %q", res.Output)
+	}
+}
+
+func TestPTYCancellation(t *testing.T) {
+	runner := newPTYRunner(t, map[string]string{
+		"lento.sh": "#!/bin/bash\nsleep 30 &\nwait\n",
+	})
+	runner.SetKillGracePeriod(200 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	res, err := runner.Run(ctx, ExecOptions{PTY: &PTYOptions{}}, "lento")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Se esperaba DeadlineExceeded, se obtuvo %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("La cancelación tardó demasiado: %v", elapsed)
+	}
+	if res.Success() {
+		t.Errorf("El script no debía terminar con éxito")
+	}
+}
+
+func TestPTYStdinNotConsumedAfterExit(t *testing.T) {
+	runner := newPTYRunner(t, map[string]string{
+		"linea.sh": "#!/bin/bash\nread -r line\necho \"leído: $line\"\n",
+	})
+
+	// Una tubería de os.Pipe admite plazos; la creada con syscall.Pipe es bloqueante, como
+	// el os.Stdin heredado de una terminal
+	pipes := map[string]func() (*os.File, *os.File){
+		"os.Pipe": func() (*os.File, *os.File) {
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return r, w
+		},
+		"descriptor bloqueante": func() (*os.File, *os.File) {
+			var fds [2]int
+			if err := syscall.Pipe(fds[:]); err != nil {
+				t.Fatal(err)
+			}
+			return os.NewFile(uintptr(fds[0]), "stdin"), os.NewFile(uintptr(fds[1]), "stdin-w")
+		},
+	}
+
+	for name, newPipe := range pipes {
+		t.Run(name, func(t *testing.T) {
+			r, w := newPipe()
+			defer r.Close()
+			defer w.Close()
+
+			if _, err := io.WriteString(w, "primera\n"); err != nil {
+				t.Fatal(err)
+			}
+			res, err := runner.Run(context.Background(), ExecOptions{PTY: &PTYOptions{}, Stdin: r}, "linea")
+			if err != nil {
+				t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+			}
+			if !strings.Contains(res.Output, "leído: primera") {
+				t.Errorf("Salida inesperada: %q", res.Output)
+			}
+
+			// Lo escrito tras terminar el script debe seguir llegando al llamador
+			if _, err := io.WriteString(w, "segunda\n"); err != nil {
+				t.Fatal(err)
+			}
+			line := make(chan string, 1)
+			go func() {
+				buf := make([]byte, 64)
+				n, _ := r.Read(buf)
+				line <- string(buf[:n])
+			}()
+			select {
+			case got := <-line:
+				if got != "segunda\n" {
+					t.Errorf("Se esperaba recibir la segunda línea, se obtuvo %q", got)
+				}
+			case <-time.After(2 * time.Second):
+				w.Close()
+				t.Errorf("La segunda línea no llegó al llamador")
+			}
+		})
+	}
+}
+
+func TestPTYPipelineUnsupported(t *testing.T) {
+	runner := newPTYRunner(t, map[string]string{"a.sh": "#!/bin/bash\necho a\n"})
+	_, err := runner.Pipeline().Pipe("a").Run(context.Background(), ExecOptions{PTY: &PTYOptions{}})
+	if err == nil {
+		t.Error("Se esperaba error al usar PTY en una tubería")
+	}
+}
+
+func TestPTYExpect(t *testing.T) {
+	runner := NewBashRunner()
+	runner.SetWorkspaceRoot(t.TempDir())
+
+	// Sin go.mod go-rename-project.sh pregunta con read -p, que solo se muestra en una terminal
+	opts := ExecOptions{
+		Dir: t.TempDir(),
+		PTY: &PTYOptions{},
+		Expect: &ExpectOptions{Rules: []ExpectRule{
+			{Pattern: `Continue anyway\? \(y/N\) $`, Response: "n", Timeout: 5 * time.Second},
+		}},
+	}
+	res, err := runner.Run(context.Background(), opts, "go-rename-project", "viejo", "nuevo")
+	if err == nil || res.ExitCode != 1 {
+		t.Fatalf("Se esperaba que el script se cancelara: %v\n%s", err, res.Output)
+	}
+	if !strings.Contains(res.Output, "Operation cancelled by user") {
+		t.Errorf("Salida inesperada: %q", res.Output)
+	}
+	if len(res.Expects) != 1 || res.Expects[0].Response != "n" {
+		t.Errorf("Respuestas inesperadas: %+v", res.Expects)
+	}
+}
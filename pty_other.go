@@ -0,0 +1,34 @@
+//go:build !linux
+
+package gorunscript
+
+import (
+	"os"
+	"os/exec"
+	"time"
+)
+
+// openPTY no está implementado fuera de Linux
+func openPTY() (master, slave *os.File, err error) {
+	return nil, nil, ErrPTYUnsupported
+}
+
+func setWindowSize(f *os.File, size WindowSize) error {
+	return ErrPTYUnsupported
+}
+
+// TerminalSize devuelve el tamaño de la terminal f; fuera de Linux siempre devuelve
+// ErrPTYUnsupported
+func TerminalSize(f *os.File) (WindowSize, error) {
+	return WindowSize{}, ErrPTYUnsupported
+}
+
+func configurePTY(cmd *exec.Cmd) {}
+
+func makeRaw(f *os.File) (func(), error) {
+	return nil, ErrPTYUnsupported
+}
+
+func waitReadable(f *os.File, timeout time.Duration) (bool, error) {
+	return false, ErrPTYUnsupported
+}
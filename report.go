@@ -0,0 +1,171 @@
+package gorunscript
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ReportKind clasifica una entrada del informe de un script
+type ReportKind string
+
+const (
+	ReportOK      ReportKind = "ok"      // Paso completado: "=>OK" de addOKmessage
+	ReportSuccess ReportKind = "success" // Mensaje en verde de success
+	ReportWarning ReportKind = "warning" // Mensaje en amarillo de warning
+	ReportError   ReportKind = "error"   // Mensaje en rojo de error o "¡ERROR!" de addERRORmessage
+)
+
+// ReportEntry es un mensaje del informe
+type ReportEntry struct {
+	Kind    ReportKind
+	Message string // Texto sin códigos de color
+}
+
+// Report es el informe estructurado que los scripts escriben con los auxiliares de
+// functions.sh (success, warning, error, addOKmessage, addERRORmessage), en orden
+type Report struct {
+	Entries []ReportEntry
+}
+
+// OK devuelve los pasos completados
+func (r Report) OK() []string {
+	return r.messages(ReportOK)
+}
+
+// Successes devuelve los mensajes de éxito
+func (r Report) Successes() []string {
+	return r.messages(ReportSuccess)
+}
+
+// Warnings devuelve las advertencias
+func (r Report) Warnings() []string {
+	return r.messages(ReportWarning)
+}
+
+// Errors devuelve los errores
+func (r Report) Errors() []string {
+	return r.messages(ReportError)
+}
+
+// HasErrors indica si el script informó de algún error
+func (r Report) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+func (r Report) messages(kind ReportKind) []string {
+	var msgs []string
+	for _, e := range r.Entries {
+		if e.Kind == kind {
+			msgs = append(msgs, e.Message)
+		}
+	}
+	return msgs
+}
+
+// Marcadores de functions.sh
+const (
+	okMarker    = "=>OK"
+	errorMarker = "¡ERROR!"
+	errorPrefix = "Error:"
+)
+
+// ansiColor reconoce una secuencia SGR como \033[0;32m
+var ansiColor = regexp.MustCompile("\x1b\\[([0-9;]*)m")
+
+// colorSpan es un fragmento de texto escrito con un color
+type colorSpan struct {
+	color string // "red", "green", "yellow" o vacío si no tiene color reconocido
+	text  string
+}
+
+// ParseReport extrae el informe de la salida de un script que usa los auxiliares de
+// functions.sh. Reconoce los marcadores "=>OK" y "¡ERROR!" y los colores verde (éxito),
+// amarillo (advertencia) y rojo (error); el texto sin color se ignora.
+func ParseReport(output string) Report {
+	spans := colorSpans(output)
+
+	var report Report
+	for i := 0; i < len(spans); i++ {
+		span := spans[i]
+		msg := strings.TrimSpace(span.text)
+		if span.color == "" || msg == "" {
+			continue
+		}
+
+		// Los marcadores van seguidos, tras un espacio, del mensaje en su propio color
+		if msg == okMarker || msg == errorMarker {
+			if next := nextColored(spans, i); next > 0 {
+				kind := ReportOK
+				if msg == errorMarker {
+					kind = ReportError
+				}
+				i = next
+				report.Entries = append(report.Entries, ReportEntry{Kind: kind, Message: strings.TrimSpace(spans[i].text)})
+				continue
+			}
+		}
+
+		switch span.color {
+		case "green":
+			report.Entries = append(report.Entries, ReportEntry{Kind: ReportSuccess, Message: msg})
+		case "yellow":
+			report.Entries = append(report.Entries, ReportEntry{Kind: ReportWarning, Message: msg})
+		case "red":
+			msg = strings.TrimSpace(strings.TrimPrefix(msg, errorPrefix))
+			report.Entries = append(report.Entries, ReportEntry{Kind: ReportError, Message: msg})
+		}
+	}
+	return report
+}
+
+// nextColored devuelve el índice del siguiente fragmento coloreado tras i si entre ambos
+// solo hay espacios en blanco, o -1
+func nextColored(spans []colorSpan, i int) int {
+	for j := i + 1; j < len(spans); j++ {
+		if spans[j].color != "" {
+			return j
+		}
+		if strings.TrimSpace(spans[j].text) != "" {
+			break
+		}
+	}
+	return -1
+}
+
+// colorSpans divide text en fragmentos según los códigos de color que lo preceden. Un
+// fragmento coloreado termina en el siguiente código, por lo que puede ocupar varias líneas.
+func colorSpans(text string) []colorSpan {
+	var spans []colorSpan
+	color := ""
+	last := 0
+
+	for _, loc := range ansiColor.FindAllStringSubmatchIndex(text, -1) {
+		if loc[0] > last {
+			spans = append(spans, colorSpan{color: color, text: text[last:loc[0]]})
+		}
+		color = sgrColor(text[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	if last < len(text) {
+		spans = append(spans, colorSpan{color: color, text: text[last:]})
+	}
+	return spans
+}
+
+// sgrColor traduce los parámetros de una secuencia SGR al color de primer plano que fija
+func sgrColor(params string) string {
+	color := ""
+	for _, p := range strings.Split(params, ";") {
+		switch p {
+		case "", "0", "39":
+			color = ""
+		case "31", "91":
+			color = "red"
+		case "32", "92":
+			color = "green"
+		case "33", "93":
+			color = "yellow"
+		}
+	}
+	return color
+}
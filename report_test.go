@@ -0,0 +1,73 @@
+package gorunscript
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseReport(t *testing.T) {
+	output := "preparando\n" +
+		"\x1b[0;32mScript ejecutado con éxito\x1b[0m\n" +
+		"\x1b[0;33mHay cambios pendientes\x1b[0m\n" +
+		"\x1b[0;31mError: Error al crear el commit fatal: sin cambios\nsegunda línea\x1b[0m\n" +
+		"\n\x1b[0;33m=>OK\x1b[0m \x1b[0;32mcambios añadidos\x1b[0m" +
+		"\n\x1b[0;31m¡ERROR!\x1b[0m \x1b[0;31mpush rechazado\x1b[0m\n" +
+		"\x1b[1;92mnegrita\x1b[m sin color\n"
+
+	want := []ReportEntry{
+		{Kind: ReportSuccess, Message: "Script ejecutado con éxito"},
+		{Kind: ReportWarning, Message: "Hay cambios pendientes"},
+		{Kind: ReportError, Message: "Error al crear el commit fatal: sin cambios\nsegunda línea"},
+		{Kind: ReportOK, Message: "cambios añadidos"},
+		{Kind: ReportError, Message: "push rechazado"},
+		{Kind: ReportSuccess, Message: "negrita"},
+	}
+
+	report := ParseReport(output)
+	if !reflect.DeepEqual(report.Entries, want) {
+		t.Errorf("Informe inesperado:\n got: %q\nwant: %q", report.Entries, want)
+	}
+	if !reflect.DeepEqual(report.OK(), []string{"cambios añadidos"}) {
+		t.Errorf("Pasos inesperados: %q", report.OK())
+	}
+	if len(report.Warnings()) != 1 || len(report.Errors()) != 2 || !report.HasErrors() {
+		t.Errorf("Clasificación inesperada: %+v", report)
+	}
+
+	if got := ParseReport("sin marcadores\n"); len(got.Entries) != 0 || got.HasErrors() {
+		t.Errorf("No se esperaban entradas: %+v", got)
+	}
+}
+
+func TestRunReport(t *testing.T) {
+	runner := NewBashRunner()
+	runner.SetWorkspaceRoot(t.TempDir())
+
+	t.Run("Pasos completados", func(t *testing.T) {
+		res, err := runner.Run(context.Background(), ExecOptions{}, "test-script", "a")
+		if err != nil {
+			t.Fatalf("Error inesperado: %v\n%s", err, res.Output)
+		}
+
+		wantOK := []string{"Número de argumentos: 1", "Argumentos recibidos: a", "Ejecución exitosa del comando"}
+		if !reflect.DeepEqual(res.Report.OK(), wantOK) {
+			t.Errorf("Pasos inesperados: %q", res.Report.OK())
+		}
+		if !reflect.DeepEqual(res.Report.Successes(), []string{"Script ejecutado con éxito"}) {
+			t.Errorf("Éxitos inesperados: %q", res.Report.Successes())
+		}
+		if res.Report.HasErrors() {
+			t.Errorf("No se esperaban errores: %q", res.Report.Errors())
+		}
+	})
+
+	t.Run("Errores", func(t *testing.T) {
+		res, _ := runner.Run(context.Background(), ExecOptions{}, "test-script", "error")
+
+		wantErrors := []string{"Error solicitado! Se recibió el argumento 'error'", "Se solicitó finalizar con error"}
+		if !reflect.DeepEqual(res.Report.Errors(), wantErrors) {
+			t.Errorf("Errores inesperados: %q", res.Report.Errors())
+		}
+	})
+}
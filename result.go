@@ -0,0 +1,45 @@
+package gorunscript
+
+import (
+	"os"
+	"time"
+)
+
+// Result describe una ejecución completa de un script
+type Result struct {
+	Script      string   // Nombre del script con extensión, p. ej. "pu.sh"
+	ScriptPath  string   // Ruta resuelta del script ejecutado
+	Layer       string   // Capa de la que proviene el script
+	Interpreter string   // Intérprete utilizado
+	Args        []string // Argumentos pasados al script
+	Function    string   // Función invocada con ExecuteFunction, vacío en otro caso
+
+	Stdout string // Salida estándar capturada
+	Stderr string // Salida de error capturada
+	Output string // stdout y stderr combinados en orden de llegada
+
+	// Report son los mensajes de éxito, advertencia y error que el script escribió con los
+	// auxiliares de functions.sh, extraídos de Output
+	Report Report
+
+	// Events son los eventos que el script envió por el descriptor de EventFDEnv, en orden
+	Events []Event
+
+	// Outputs son las salidas con nombre que el script publicó en el archivo de
+	// ResultFileEnv; DecodeOutputs las convierte en un struct
+	Outputs map[string]string
+
+	// Expects son las preguntas que ExecOptions.Expect respondió, en orden
+	Expects []ExpectMatch
+
+	ExitCode int       // Código de salida; -1 si el proceso terminó por una señal
+	Signal   os.Signal // Señal que terminó el proceso, nil si salió normalmente
+
+	StartedAt time.Time     // Momento de inicio del proceso
+	Duration  time.Duration // Tiempo total hasta que el proceso terminó
+}
+
+// Success indica si el script terminó con código de salida cero
+func (r *Result) Success() bool {
+	return r.ExitCode == 0 && r.Signal == nil
+}
@@ -0,0 +1,38 @@
+package gorunscript
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunResult(t *testing.T) {
+	projectRoot := newTempProject(t, map[string]string{
+		"result.sh": "#!/bin/bash\necho salida\necho fallo >&2\nsleep 0.05\nexit 7\n",
+	})
+	runner := NewBashRunnerWithOptions(projectRoot)
+	runner.SetWorkspaceRoot(t.TempDir())
+
+	before := time.Now()
+	res, err := runner.Run(context.Background(), ExecOptions{}, "result", "a", "b")
+	if err == nil {
+		t.Fatal("Se esperaba un error por código de salida distinto de cero")
+	}
+
+	if res.ExitCode != 7 || res.Signal != nil || res.Success() {
+		t.Errorf("Terminación inesperada: código %d, señal %v", res.ExitCode, res.Signal)
+	}
+	if res.Stdout != "salida\n" || res.Stderr != "fallo\n" {
+		t.Errorf("Flujos inesperados: stdout %q, stderr %q", res.Stdout, res.Stderr)
+	}
+	if res.Script != "result.sh" || filepath.Base(res.ScriptPath) != "result.sh" {
+		t.Errorf("Script resuelto inesperado: %s (%s)", res.Script, res.ScriptPath)
+	}
+	if res.Interpreter == "" || len(res.Args) != 2 || res.Args[1] != "b" {
+		t.Errorf("Intérprete o argumentos inesperados: %q %v", res.Interpreter, res.Args)
+	}
+	if res.StartedAt.Before(before) || res.Duration < 50*time.Millisecond {
+		t.Errorf("Tiempos inesperados: inicio %v, duración %v", res.StartedAt, res.Duration)
+	}
+}
@@ -0,0 +1,137 @@
+package gorunscript
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+// StreamKind identifica el flujo de salida del que proviene una línea
+type StreamKind string
+
+const (
+	StreamStdout StreamKind = "stdout"
+	StreamStderr StreamKind = "stderr"
+)
+
+// StreamOptions define hacia dónde se transmite la salida de un script mientras se ejecuta.
+// Todos los campos son opcionales; stdout y stderr se mantienen siempre separados.
+type StreamOptions struct {
+	Stdout io.Writer // Recibe stdout a medida que el script lo escribe
+	Stderr io.Writer // Recibe stderr a medida que el script lo escribe
+
+	// OnLine se invoca por cada línea completa (sin el salto de línea final).
+	// Las llamadas se serializan, nunca se ejecutan en paralelo.
+	OnLine func(stream StreamKind, line string)
+}
+
+// capturedOutput es el texto capturado de una ejecución
+type capturedOutput struct {
+	combined string // stdout y stderr en el orden en que llegaron
+	stdout   string
+	stderr   string
+}
+
+// streamCapture reparte la salida del proceso entre los buffers internos y los destinos del usuario
+type streamCapture struct {
+	mu       sync.Mutex // Protege combined y serializa OnLine
+	combined bytes.Buffer
+	stdout   bytes.Buffer
+	stderr   bytes.Buffer
+
+	streams   StreamOptions
+	stdoutLns *lineSplitter
+	stderrLns *lineSplitter
+}
+
+func newStreamCapture(streams StreamOptions) *streamCapture {
+	c := &streamCapture{streams: streams}
+	if streams.OnLine != nil {
+		c.stdoutLns = &lineSplitter{emit: c.lineEmitter(StreamStdout)}
+		c.stderrLns = &lineSplitter{emit: c.lineEmitter(StreamStderr)}
+	}
+	return c
+}
+
+// stdoutWriter devuelve el destino para el stdout del proceso
+func (c *streamCapture) stdoutWriter() io.Writer {
+	return c.writerFor(&c.stdout, c.streams.Stdout, c.stdoutLns)
+}
+
+// stderrWriter devuelve el destino para el stderr del proceso
+func (c *streamCapture) stderrWriter() io.Writer {
+	return c.writerFor(&c.stderr, c.streams.Stderr, c.stderrLns)
+}
+
+func (c *streamCapture) writerFor(own *bytes.Buffer, user io.Writer, lines *lineSplitter) io.Writer {
+	writers := []io.Writer{own, &lockedWriter{mu: &c.mu, w: &c.combined}}
+	if lines != nil {
+		writers = append(writers, lines)
+	}
+	if user != nil {
+		writers = append(writers, user)
+	}
+	return io.MultiWriter(writers...)
+}
+
+func (c *streamCapture) lineEmitter(kind StreamKind) func(string) {
+	return func(line string) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.streams.OnLine(kind, line)
+	}
+}
+
+// finish entrega las líneas incompletas pendientes y devuelve el texto capturado.
+// Solo debe llamarse cuando el proceso ya terminó de escribir.
+func (c *streamCapture) finish() capturedOutput {
+	if c.stdoutLns != nil {
+		c.stdoutLns.flush()
+		c.stderrLns.flush()
+	}
+	return capturedOutput{
+		combined: c.combined.String(),
+		stdout:   c.stdout.String(),
+		stderr:   c.stderr.String(),
+	}
+}
+
+// lockedWriter serializa escrituras concurrentes sobre un mismo destino
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (lw *lockedWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.w.Write(p)
+}
+
+// lineSplitter acumula bytes y emite cada línea completa
+type lineSplitter struct {
+	pending []byte
+	emit    func(line string)
+}
+
+func (ls *lineSplitter) Write(p []byte) (int, error) {
+	ls.pending = append(ls.pending, p...)
+	for {
+		i := bytes.IndexByte(ls.pending, '\n')
+		if i < 0 {
+			break
+		}
+		ls.emit(strings.TrimSuffix(string(ls.pending[:i]), "\r"))
+		ls.pending = ls.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush emite la última línea si el proceso no terminó con salto de línea
+func (ls *lineSplitter) flush() {
+	if len(ls.pending) > 0 {
+		ls.emit(strings.TrimSuffix(string(ls.pending), "\r"))
+		ls.pending = nil
+	}
+}
@@ -0,0 +1,60 @@
+package gorunscript
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestExecuteScriptStream(t *testing.T) {
+	projectRoot := newTempProject(t, map[string]string{
+		"streams.sh": "#!/bin/bash\necho uno\necho advertencia >&2\necho dos\nprintf 'sin salto'\n",
+	})
+	runner := NewBashRunnerWithOptions(projectRoot)
+	runner.SetWorkspaceRoot(t.TempDir())
+
+	t.Run("Flujos separados hacia writers", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		streams := StreamOptions{Stdout: &stdout, Stderr: &stderr}
+
+		exitCode, capturedOut, capturedErr, err := runner.ExecuteScriptStream(context.Background(), streams, "streams")
+		if err != nil || exitCode != 0 {
+			t.Fatalf("Ejecución fallida (código %d): %v", exitCode, err)
+		}
+
+		if stdout.String() != "uno\ndos\nsin salto" {
+			t.Errorf("stdout transmitido inesperado: %q", stdout.String())
+		}
+		if stderr.String() != "advertencia\n" {
+			t.Errorf("stderr transmitido inesperado: %q", stderr.String())
+		}
+		if capturedOut != stdout.String() || capturedErr != stderr.String() {
+			t.Errorf("El texto capturado no coincide con el transmitido: %q / %q", capturedOut, capturedErr)
+		}
+	})
+
+	t.Run("Callback por línea", func(t *testing.T) {
+		var (
+			mu    sync.Mutex
+			lines = map[StreamKind][]string{}
+		)
+		streams := StreamOptions{OnLine: func(stream StreamKind, line string) {
+			mu.Lock()
+			defer mu.Unlock()
+			lines[stream] = append(lines[stream], line)
+		}}
+
+		if _, _, _, err := runner.ExecuteScriptStream(context.Background(), streams, "streams"); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := strings.Join(lines[StreamStdout], "|"); got != "uno|dos|sin salto" {
+			t.Errorf("Líneas de stdout inesperadas: %q", got)
+		}
+		if got := strings.Join(lines[StreamStderr], "|"); got != "advertencia" {
+			t.Errorf("Líneas de stderr inesperadas: %q", got)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Diagnostic es un problema de sintaxis encontrado al validar un script
+type Diagnostic struct {
+	Script  string // Nombre del script con extensión
+	Line    int    // Línea del problema empezando en 1; 0 si el intérprete no la indicó
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.Script, d.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", d.Script, d.Line, d.Message)
+}
+
+// ValidationError se devuelve cuando la validación previa configurada con
+// SetValidateBeforeRun encuentra errores de sintaxis. Satisface errors.Is(err, ErrValidation).
+type ValidationError struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		msgs[i] = d.String()
+	}
+	return fmt.Sprintf("error de sintaxis en los scripts:\n%s", strings.Join(msgs, "\n"))
+}
+
+// Is permite comparar con ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// SetValidateBeforeRun configura si antes de cada ejecución se valida la sintaxis del script
+// y de sus dependencias. Si hay diagnósticos no se ejecuta nada y se devuelve un *ValidationError.
+func (sr *ScriptRunner) SetValidateBeforeRun(validate bool) {
+	sr.validateBeforeRun = validate
+}
+
+// Validate analiza la sintaxis de los scripts indicados, o de todo el catálogo si no se
+// indica ninguno, con el modo sin ejecución de su intérprete (`bash -n`, `sh -n`,
+// `ast.parse` de Python). Los scripts cuyo intérprete no tiene ese modo se omiten.
+// Un script con errores no es un fallo de Validate: se informa en los diagnósticos.
+func (sr *ScriptRunner) Validate(ctx context.Context, scriptNames ...string) ([]Diagnostic, error) {
+	inv, err := sr.prepare(ctx, ExecOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer inv.release()
+
+	scripts := inv.files
+	if len(scriptNames) > 0 {
+		scripts = make([]scriptFile, len(scriptNames))
+		for i, name := range scriptNames {
+			script, ok := findScript(inv.files, name, sr.interpreters)
+			if !ok {
+				return nil, newScriptNotFoundError(script.name, inv.files)
+			}
+			scripts[i] = script
+		}
+	}
+
+	// Los scripts se analizan sobre la caché, igual que se ejecutarían
+	inv.scriptsDir, err = prepareScriptCache(inv.files, inv.files, inv.cacheRoot)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExtraction, err)
+	}
+
+	return sr.checkScripts(ctx, inv.scriptsDir, scripts)
+}
+
+// checkScripts analiza cada script ya extraído en dir
+func (sr *ScriptRunner) checkScripts(ctx context.Context, dir string, scripts []scriptFile) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+	for _, script := range scripts {
+		scriptPath := filepath.Join(dir, script.name)
+		name, args, ok := sr.interpreterFor(script).checkLine(filepath.ToSlash(scriptPath))
+		if !ok {
+			continue
+		}
+
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err == nil {
+			continue
+		}
+
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+				return diagnostics, fmt.Errorf("%w: %s: %w", ErrInterpreterNotFound, name, err)
+			}
+			return diagnostics, fmt.Errorf("error validando script %s: %w", script.name, err)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return diagnostics, fmt.Errorf("error validando script %s: %w", script.name, ctxErr)
+		}
+
+		diagnostics = append(diagnostics, parseDiagnostics(script.name, string(output))...)
+	}
+	return diagnostics, nil
+}
+
+var (
+	// bash: "/ruta/x.sh: line 3: syntax error near unexpected token `fi'"
+	bashDiagnostic = regexp.MustCompile(`^.*?: line (\d+): (.*)$`)
+	// dash: "/ruta/x.sh: 3: Syntax error: "fi" unexpected"
+	shDiagnostic = regexp.MustCompile(`^.*?: (\d+): (.*)$`)
+	// python: `  File "/ruta/x.py", line 1`
+	pythonLocation = regexp.MustCompile(`^\s*File ".*", line (\d+)`)
+)
+
+// parseDiagnostics convierte la salida de error de un intérprete en diagnósticos.
+// Si no reconoce el formato devuelve la salida completa como un único diagnóstico.
+func parseDiagnostics(script, output string) []Diagnostic {
+	var diagnostics []Diagnostic
+	pythonLine := 0
+	var last string
+
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) != "" {
+			last = strings.TrimSpace(line)
+		}
+
+		if m := pythonLocation.FindStringSubmatch(line); m != nil {
+			pythonLine, _ = strconv.Atoi(m[1])
+			continue
+		}
+		for _, re := range []*regexp.Regexp{bashDiagnostic, shDiagnostic} {
+			if m := re.FindStringSubmatch(line); m != nil {
+				n, _ := strconv.Atoi(m[1])
+				diagnostics = append(diagnostics, Diagnostic{Script: script, Line: n, Message: m[2]})
+				break
+			}
+		}
+	}
+
+	if pythonLine > 0 {
+		// El mensaje de Python es la última línea, p. ej. "SyntaxError: invalid syntax"
+		return []Diagnostic{{Script: script, Line: pythonLine, Message: last}}
+	}
+	if len(diagnostics) == 0 {
+		return []Diagnostic{{Script: script, Message: strings.TrimSpace(output)}}
+	}
+	return diagnostics
+}
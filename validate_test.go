@@ -0,0 +1,155 @@
+package gorunscript
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// TestValidateEmbeddedScripts comprueba la sintaxis de todos los scripts de bash_scripts
+func TestValidateEmbeddedScripts(t *testing.T) {
+	runner := NewBashRunner()
+	runner.SetWorkspaceRoot(t.TempDir())
+
+	diagnostics, err := runner.Validate(context.Background())
+	if err != nil {
+		t.Fatalf("Error validando scripts: %v", err)
+	}
+	for _, d := range diagnostics {
+		t.Errorf("Error de sintaxis: %s", d)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ok.sh":     {Data: []byte("#!/bin/bash\necho ok\n")},
+		"roto.sh":   {Data: []byte("#!/bin/bash\nif true; then\n  echo hola\nfi fi\n")},
+		"posix.sh":  {Data: []byte("#!/bin/sh\ncase x in\n")},
+		"usa.sh":    {Data: []byte("#!/bin/bash\nsource roto.sh\n")},
+		"script.py": {Data: []byte("def f(:\n    pass\n")},
+	}
+
+	newRunner := func(t *testing.T) *ScriptRunner {
+		runner := NewScriptRunner(fsys, ".", "bash")
+		runner.SetWorkspaceRoot(t.TempDir())
+		return runner
+	}
+
+	t.Run("Un script concreto", func(t *testing.T) {
+		diagnostics, err := newRunner(t).Validate(context.Background(), "roto")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(diagnostics) == 0 {
+			t.Fatal("Se esperaban diagnósticos para roto.sh")
+		}
+		d := diagnostics[0]
+		if d.Script != "roto.sh" || d.Line != 4 || !strings.Contains(d.Message, "syntax error") {
+			t.Errorf("Diagnóstico inesperado: %+v", d)
+		}
+	})
+
+	t.Run("Un script válido", func(t *testing.T) {
+		diagnostics, err := newRunner(t).Validate(context.Background(), "ok.sh")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(diagnostics) != 0 {
+			t.Errorf("No se esperaban diagnósticos: %v", diagnostics)
+		}
+	})
+
+	t.Run("Todo el catálogo", func(t *testing.T) {
+		diagnostics, err := newRunner(t).Validate(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var scripts []string
+		for _, d := range diagnostics {
+			if len(scripts) == 0 || scripts[len(scripts)-1] != d.Script {
+				scripts = append(scripts, d.Script)
+			}
+		}
+		want := []string{"posix.sh", "roto.sh"}
+		if _, err := exec.LookPath("python3"); err == nil {
+			want = []string{"posix.sh", "roto.sh", "script.py"}
+		}
+		if !reflect.DeepEqual(scripts, want) {
+			t.Errorf("Se esperaban diagnósticos de %v, se obtuvieron: %v", want, diagnostics)
+		}
+	})
+
+	t.Run("Script inexistente", func(t *testing.T) {
+		_, err := newRunner(t).Validate(context.Background(), "falta")
+		if !errors.Is(err, ErrScriptNotFound) {
+			t.Errorf("Se esperaba ErrScriptNotFound, se obtuvo: %v", err)
+		}
+	})
+
+	t.Run("Validación antes de ejecutar", func(t *testing.T) {
+		runner := newRunner(t)
+		runner.SetValidateBeforeRun(true)
+
+		res, err := runner.Run(context.Background(), ExecOptions{}, "usa")
+		if !errors.Is(err, ErrValidation) {
+			t.Fatalf("Se esperaba ErrValidation, se obtuvo: %v", err)
+		}
+		var valErr *ValidationError
+		if !errors.As(err, &valErr) || valErr.Diagnostics[0].Script != "roto.sh" {
+			t.Errorf("Se esperaban diagnósticos de la dependencia roto.sh: %v", err)
+		}
+		if !res.StartedAt.IsZero() {
+			t.Error("El script no debía iniciarse")
+		}
+
+		if _, err := runner.Run(context.Background(), ExecOptions{}, "ok"); err != nil {
+			t.Errorf("Error inesperado con un script válido: %v", err)
+		}
+	})
+}
+
+func TestParseDiagnostics(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []Diagnostic
+	}{
+		{
+			name:   "bash",
+			output: "/tmp/x/roto.sh: line 3: syntax error near unexpected token `fi'\n/tmp/x/roto.sh: line 3: `fi fi'\n",
+			want: []Diagnostic{
+				{Script: "roto.sh", Line: 3, Message: "syntax error near unexpected token `fi'"},
+				{Script: "roto.sh", Line: 3, Message: "`fi fi'"},
+			},
+		},
+		{
+			name:   "sh",
+			output: "/tmp/x/roto.sh: 3: Syntax error: \"fi\" unexpected\n",
+			want:   []Diagnostic{{Script: "roto.sh", Line: 3, Message: "Syntax error: \"fi\" unexpected"}},
+		},
+		{
+			name:   "python",
+			output: "  File \"/tmp/x/roto.py\", line 1\n    def f(:\n          ^\nSyntaxError: invalid syntax\n",
+			want:   []Diagnostic{{Script: "roto.sh", Line: 1, Message: "SyntaxError: invalid syntax"}},
+		},
+		{
+			name:   "Formato desconocido",
+			output: "algo salió mal\n",
+			want:   []Diagnostic{{Script: "roto.sh", Message: "algo salió mal"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseDiagnostics("roto.sh", tt.output)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\n got: %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package gorunscript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	workspacePrefix = "ws-"   // Prefijo de los directorios de espacio de trabajo
+	lockSuffix      = ".lock" // Archivo de bloqueo hermano de cada espacio de trabajo
+	keepSuffix      = ".keep" // Marca de espacio de trabajo conservado tras la ejecución
+
+	// orphanWorkspaceAge es la antigüedad a partir de la cual un espacio de trabajo sin
+	// archivo de bloqueo se considera abandonado (el proceso murió antes de crearlo)
+	orphanWorkspaceAge = time.Minute
+)
+
+// workspace es un directorio aislado donde se prepara y ejecuta una invocación.
+// Mientras existe, el archivo de bloqueo asociado permanece bloqueado por este proceso,
+// lo que permite a otros procesos distinguirlo de los restos de un proceso caído.
+type workspace struct {
+	dir  string
+	lock *os.File
+}
+
+// newWorkspace crea un espacio de trabajo único bajo root, recolectando antes los abandonados
+func newWorkspace(root string) (*workspace, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("error creando directorio para scripts: %w", err)
+	}
+
+	// Los errores de recolección no impiden la ejecución actual
+	_ = collectWorkspaces(root, false)
+
+	// El bloqueo se crea y se toma antes que el directorio: así ningún recolector puede
+	// observar el directorio con su archivo de bloqueo todavía libre
+	lock, err := os.CreateTemp(root, workspacePrefix+"*"+lockSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("error creando espacio de trabajo: %w", err)
+	}
+	if err := lockFile(lock); err != nil {
+		releaseLock(lock)
+		_ = os.Remove(lock.Name())
+		return nil, fmt.Errorf("error bloqueando espacio de trabajo: %w", err)
+	}
+
+	dir := strings.TrimSuffix(lock.Name(), lockSuffix)
+	if err := os.Mkdir(dir, 0755); err != nil {
+		releaseLock(lock)
+		_ = os.Remove(lock.Name())
+		return nil, fmt.Errorf("error creando espacio de trabajo: %w", err)
+	}
+
+	return &workspace{dir: dir, lock: lock}, nil
+}
+
+// release libera el bloqueo del espacio de trabajo y lo elimina salvo que deba conservarse
+func (ws *workspace) release(keep bool) {
+	if keep {
+		if f, err := os.Create(ws.dir + keepSuffix); err == nil {
+			_ = f.Close()
+		}
+	} else {
+		_ = os.RemoveAll(ws.dir)
+	}
+
+	releaseLock(ws.lock)
+	_ = os.Remove(ws.dir + lockSuffix)
+}
+
+// collectWorkspaces elimina los espacios de trabajo bajo root cuyo proceso ya no existe.
+// Los conservados con SetKeepScripts solo se eliminan cuando includeKept es true.
+func collectWorkspaces(root string, includeKept bool) error {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error leyendo directorio de espacios de trabajo: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || !strings.HasPrefix(name, workspacePrefix) {
+			continue
+		}
+
+		dir := filepath.Join(root, name)
+		kept := fileExists(dir + keepSuffix)
+		if kept && !includeKept {
+			continue
+		}
+
+		if !workspaceAbandoned(dir, kept) {
+			continue
+		}
+
+		_ = os.RemoveAll(dir)
+		_ = os.Remove(dir + keepSuffix)
+		_ = os.Remove(dir + lockSuffix)
+	}
+
+	return nil
+}
+
+// workspaceAbandoned indica si ningún proceso vivo está usando el espacio de trabajo
+func workspaceAbandoned(dir string, kept bool) bool {
+	lockPath := dir + lockSuffix
+	if fileExists(lockPath) {
+		return lockIsStale(lockPath)
+	}
+
+	// Los conservados liberan su bloqueo al terminar
+	if kept {
+		return true
+	}
+
+	// Sin archivo de bloqueo puede tratarse de un espacio recién creado por otro proceso
+	info, err := os.Stat(dir)
+	if err != nil {
+		return false
+	}
+	return time.Since(info.ModTime()) > orphanWorkspaceAge
+}
+
+// fileExists indica si la ruta existe
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,100 @@
+package gorunscript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParallelExecution(t *testing.T) {
+	// Cada ejecución escribe en su directorio de trabajo y verifica que nadie más lo tocó
+	projectRoot := newTempProject(t, map[string]string{
+		"isolated.sh": "#!/bin/bash\necho \"$1\" > owner.txt\nsleep 0.2\n" +
+			"[ \"$(cat owner.txt)\" = \"$1\" ] || { echo 'espacio compartido'; exit 3; }\n" +
+			"[ -f \"$0\" ] || { echo 'script eliminado'; exit 4; }\necho \"ok $1\"\n",
+	})
+	runner := NewBashRunnerWithOptions(projectRoot)
+	runner.SetWorkspaceRoot(t.TempDir())
+
+	const workers = 12
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			exitCode, output, err := runner.ExecuteScript("isolated", id)
+			if err != nil || exitCode != 0 || !strings.Contains(output, "ok "+id) {
+				errs <- fmt.Errorf("ejecución %s fallida (código %d): %v\n%s", id, exitCode, err, output)
+			}
+		}(fmt.Sprintf("job-%d", i))
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestWorkspaceCollection(t *testing.T) {
+	root := t.TempDir()
+
+	// Espacio abandonado por un proceso caído: bloqueo existente pero libre
+	stale := filepath.Join(root, workspacePrefix+"stale")
+	if err := os.MkdirAll(stale, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale+lockSuffix, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Espacio en uso por otra ejecución
+	busy, err := newWorkspace(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Espacio conservado con SetKeepScripts
+	kept, err := newWorkspace(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept.release(true)
+
+	if err := collectWorkspaces(root, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if fileExists(stale) {
+		t.Error("El espacio abandonado no fue recolectado")
+	}
+	if !fileExists(busy.dir) {
+		t.Error("Se eliminó un espacio de trabajo en uso")
+	}
+	if !fileExists(kept.dir) {
+		t.Error("Se eliminó un espacio conservado sin solicitarlo")
+	}
+
+	runner := NewBashRunner()
+	runner.SetWorkspaceRoot(root)
+	if err := runner.CleanWorkspaces(); err != nil {
+		t.Fatal(err)
+	}
+
+	if fileExists(kept.dir) {
+		t.Error("CleanWorkspaces no eliminó el espacio conservado")
+	}
+	if !fileExists(busy.dir) {
+		t.Error("CleanWorkspaces eliminó un espacio de trabajo en uso")
+	}
+
+	busy.release(false)
+	if fileExists(busy.dir) || fileExists(busy.dir+lockSuffix) {
+		t.Error("release no eliminó el espacio de trabajo")
+	}
+}